@@ -28,7 +28,14 @@ func main() {
 	logger := bard.NewLogger(os.Stdout)
 
 	libpak.Build(system.Build{
-		Logger:  logger,
-		Systems: []system.System{system.Gradle{Logger: logger}, system.Maven{Logger: logger}},
+		Logger: logger,
+		Systems: []system.System{
+			system.Gradle{Logger: logger},
+			system.Maven{Logger: logger},
+			system.Sbt{Logger: logger},
+			system.Leiningen{Logger: logger},
+			system.ClojureTools{Logger: logger},
+			system.Ant{Logger: logger},
+		},
 	})
 }
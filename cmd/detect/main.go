@@ -23,6 +23,6 @@ import (
 
 func main() {
 	libpak.Detect(system.Detect{
-		Systems: []system.System{system.Gradle{}, system.Maven{}},
+		Systems: []system.System{system.Gradle{}, system.Maven{}, system.Sbt{}, system.Leiningen{}, system.ClojureTools{}, system.Ant{}},
 	})
 }
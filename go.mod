@@ -3,6 +3,8 @@ module github.com/paketo-buildpacks/build-system
 go 1.14
 
 require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/Masterminds/semver/v3 v3.1.0
 	github.com/buildpacks/libcnb v1.7.0
 	github.com/magiconair/properties v1.8.1
 	github.com/mattn/go-shellwords v1.0.10
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/crush"
+)
+
+type AntDistribution struct {
+	LayerContributor libpak.DependencyLayerContributor
+	Logger           bard.Logger
+}
+
+func (a AntDistribution) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	a.LayerContributor.Logger = a.Logger
+
+	return a.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		a.Logger.Bodyf("Expanding to %s", layer.Path)
+		if err := crush.ExtractTarGz(artifact, layer.Path, 1); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to expand Ant\n%w", err)
+		}
+
+		layer.Build = true
+		layer.Cache = true
+		return layer, nil
+	})
+}
+
+func (AntDistribution) Name() string {
+	return "ant"
+}
+
+type Ant struct {
+	Logger bard.Logger
+}
+
+func (Ant) Caches(string) ([]Cache, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return []Cache{NewCache(filepath.Join(u.HomeDir, ".ivy2"))}, nil
+}
+
+func (Ant) DefaultArguments() []string {
+	return []string{"-noinput"}
+}
+
+func (Ant) DefaultTarget() string {
+	return filepath.Join("dist", "*.[jw]ar")
+}
+
+func (Ant) Detect(context libcnb.DetectContext, result *libcnb.DetectResult) error {
+	file := filepath.Join(context.Application.Path, "build.xml")
+	_, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to determine if %s exists\n%w", file, err)
+	}
+
+	result.Pass = true
+	result.Plans = append(result.Plans, libcnb.BuildPlan{
+		Provides: []libcnb.BuildPlanProvide{
+			{Name: "ant"},
+			{Name: "jvm-application"},
+		},
+		Requires: []libcnb.BuildPlanRequire{
+			{Name: "ant"},
+			{Name: "jdk"},
+		},
+	})
+
+	return nil
+}
+
+func (Ant) Distribution(layersPath string) string {
+	return filepath.Join(layersPath, "ant", "bin", "ant")
+}
+
+func (a Ant) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	dep, err := resolver.Resolve("ant", "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	return AntDistribution{
+		LayerContributor: libpak.NewDependencyLayerContributor(dep, cache, plan),
+		Logger:           a.Logger,
+	}, nil
+}
+
+func (Ant) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
+	_, ok, err := resolver.Resolve("ant")
+	if err != nil {
+		return false, fmt.Errorf("unable to resolve ant plan entry\n%w", err)
+	}
+
+	return ok, nil
+}
+
+// Wrappers returns no names as Ant has no conventional wrapper script.
+func (Ant) Wrappers() []string {
+	return nil
+}
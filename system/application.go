@@ -17,16 +17,15 @@
 package system
 
 import (
-	"archive/zip"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/buildpacks/libcnb"
-	"github.com/magiconair/properties"
 	"github.com/mattn/go-shellwords"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
@@ -35,22 +34,62 @@ import (
 	"github.com/paketo-buildpacks/libpak/sherpa"
 )
 
+// ApplicationMetadata is the expected metadata of the application layer.  The application is rebuilt whenever its
+// source files or the configuration the layout of the layer depends on change.
+type ApplicationMetadata struct {
+
+	// Explode indicates whether the primary artifact is expanded into the application root.
+	Explode bool `mapstructure:"explode" toml:"explode"`
+
+	// Files is the listing of the source files that are not ignored.
+	Files []sherpa.FileEntry `mapstructure:"files" toml:"files"`
+
+	// RunTests indicates whether tests are run, and their results collected, during the build.
+	RunTests bool `mapstructure:"run-tests" toml:"run-tests"`
+}
+
+// ExecutableArtifact is the name under which an unexpanded primary artifact is placed in the application root, so that
+// the processes launching it with java -jar are known before it is built.
+const ExecutableArtifact = "application.jar"
+
 type Application struct {
-	ApplicationPath  string
-	Command          string
-	DefaultArguments []string
-	DefaultTarget    string
-	Executor         effect.Executor
-	LayerContributor libpak.LayerContributor
-	Logger           bard.Logger
+	AdditionalArguments []string
+	ApplicationPath     string
+	Command             string
+	DefaultArguments    []string
+	DefaultTarget       string
+	Executor            effect.Executor
+	Explode             bool
+	LayerContributor    libpak.LayerContributor
+	Logger              bard.Logger
+	TestReports         []string
 }
 
+// NewApplication creates a new Application.  Unless $BP_EXPLODE_ARTIFACT is false, the primary artifact is expanded
+// into the application root.  Otherwise it is placed there unexpanded as ExecutableArtifact, to be launched with
+// java -jar.
 func NewApplication(applicationPath string, command string, defaultArguments []string, defaultTarget string) (Application, error) {
-	l, err := sherpa.NewFileListing(applicationPath)
+	ignore, err := LoadIgnore(applicationPath)
+	if err != nil {
+		return Application{}, fmt.Errorf("unable to load ignored files for %s\n%w", applicationPath, err)
+	}
+
+	l, err := NewSourceListing(applicationPath, ignore)
 	if err != nil {
 		return Application{}, fmt.Errorf("unable to create file listing for %s\n%w", applicationPath, err)
 	}
-	expected := map[string][]sherpa.FileEntry{"files": l}
+
+	explode, err := ExplodeArtifact()
+	if err != nil {
+		return Application{}, fmt.Errorf("unable to determine whether to explode artifact\n%w", err)
+	}
+
+	runTests, err := RunTests()
+	if err != nil {
+		return Application{}, fmt.Errorf("unable to determine whether to run tests\n%w", err)
+	}
+
+	expected := ApplicationMetadata{Explode: explode, Files: l, RunTests: runTests}
 
 	return Application{
 		ApplicationPath:  applicationPath,
@@ -58,6 +97,7 @@ func NewApplication(applicationPath string, command string, defaultArguments []s
 		DefaultArguments: defaultArguments,
 		DefaultTarget:    defaultTarget,
 		Executor:         effect.NewExecutor(),
+		Explode:          explode,
 		LayerContributor: libpak.NewLayerContributor("Compiled Application", expected),
 	}, nil
 }
@@ -72,30 +112,59 @@ func (a Application) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 		}
 
 		a.Logger.Bodyf("Executing %s %s", filepath.Base(a.Command), strings.Join(arguments, " "))
-		if err := a.Executor.Execute(effect.Execution{
+		execErr := a.Executor.Execute(effect.Execution{
 			Command: a.Command,
 			Args:    arguments,
 			Dir:     a.ApplicationPath,
 			Stdout:  a.Logger.InfoWriter(),
 			Stderr:  a.Logger.InfoWriter(),
-		}); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("error running build\n%w", err)
+		})
+
+		if len(a.TestReports) > 0 {
+			files, err := FindTestReports(a.ApplicationPath, a.TestReports)
+			if err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to find test reports\n%w", err)
+			}
+
+			summary, err := NewTestSummary(files)
+			if err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to summarize test reports\n%w", err)
+			}
+			a.logTestSummary(summary)
+
+			for _, f := range files {
+				rel, err := filepath.Rel(a.ApplicationPath, f)
+				if err != nil {
+					return libcnb.Layer{}, fmt.Errorf("unable to determine relative path of %s\n%w", f, err)
+				}
+
+				if err := copyFile(f, filepath.Join(layer.Path, "test-results", rel)); err != nil {
+					return libcnb.Layer{}, fmt.Errorf("unable to copy test report %s\n%w", f, err)
+				}
+			}
+
+			if execErr == nil && !summary.Passed() {
+				return libcnb.Layer{}, fmt.Errorf("tests failed: %s", summary)
+			}
 		}
 
-		artifact, err := a.ResolveArtifact()
-		if err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to resolve artifact\n%w", err)
+		if execErr != nil {
+			return libcnb.Layer{}, fmt.Errorf("error running build\n%w", execErr)
 		}
 
-		in, err := os.Open(artifact)
+		artifacts, err := a.ResolveArtifacts()
 		if err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", artifact, err)
+			return libcnb.Layer{}, fmt.Errorf("unable to resolve artifacts\n%w", err)
+		}
+
+		if err := a.writeSBOM(filepath.Join(layer.Path, "sbom"), artifacts); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write bills of materials\n%w", err)
 		}
-		defer in.Close()
 
-		file := filepath.Join(layer.Path, "application.zip")
-		if err := sherpa.CopyFile(in, file); err != nil {
-			return libcnb.Layer{}, fmt.Errorf("unable to copy %s to %s\n%w", artifact, file, err)
+		for i, artifact := range artifacts {
+			if err := a.contributeArtifact(layer, artifact, i == 0); err != nil {
+				return libcnb.Layer{}, err
+			}
 		}
 
 		layer.Cache = true
@@ -105,6 +174,24 @@ func (a Application) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 		return libcnb.Layer{}, fmt.Errorf("unable to contribute application layer\n%w", err)
 	}
 
+	includes, err := a.ResolveIncludeFiles()
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to resolve included files\n%w", err)
+	}
+
+	var preserved string
+	if len(includes) > 0 {
+		preserved, err = ioutil.TempDir("", "preserved")
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create temporary directory\n%w", err)
+		}
+		defer os.RemoveAll(preserved)
+
+		if err := a.preserveFiles(includes, preserved); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
 	a.Logger.Header("Removing source code")
 	cs, err := ioutil.ReadDir(a.ApplicationPath)
 	if err != nil {
@@ -118,23 +205,228 @@ func (a Application) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 	}
 
 	file := filepath.Join(layer.Path, "application.zip")
-	in, err := os.Open(file)
+	if _, err := os.Stat(file); err == nil {
+		in, err := os.Open(file)
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", file, err)
+		}
+		defer in.Close()
+
+		if err := crush.ExtractZip(in, a.ApplicationPath, 0); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to extract %s\n%w", file, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to stat %s\n%w", file, err)
+	}
+
+	file = filepath.Join(layer.Path, "application")
+	if _, err := os.Stat(file); err == nil {
+		if err := copyTree(file, a.ApplicationPath); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to copy %s to %s\n%w", file, a.ApplicationPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to stat %s\n%w", file, err)
+	}
+
+	file = filepath.Join(layer.Path, "artifact")
+	if cs, err := ioutil.ReadDir(file); err == nil {
+		for _, c := range cs {
+			a.Logger.Bodyf("Placing %s in application root", c.Name())
+			if err := copyFile(filepath.Join(file, c.Name()), filepath.Join(a.ApplicationPath, c.Name())); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to copy %s to %s\n%w", c.Name(), a.ApplicationPath, err)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to list children of %s\n%w", file, err)
+	}
+
+	if preserved != "" {
+		if err := copyTree(preserved, a.ApplicationPath); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to restore included files to %s\n%w", a.ApplicationPath, err)
+		}
+	}
+
+	return layer, nil
+}
+
+// preserveFiles copies the source files and directories matching the include patterns to path, keeping their
+// locations relative to the application root.
+func (a Application) preserveFiles(includes []string, path string) error {
+	for _, pattern := range includes {
+		matches, err := filepath.Glob(filepath.Join(a.ApplicationPath, pattern))
+		if err != nil {
+			return fmt.Errorf("unable to find files with %s\n%w", pattern, err)
+		}
+
+		for _, m := range matches {
+			rel, err := filepath.Rel(a.ApplicationPath, m)
+			if err != nil {
+				return fmt.Errorf("unable to determine relative path of %s\n%w", m, err)
+			}
+
+			if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("included file %s must be within the application", rel)
+			}
+
+			info, err := os.Lstat(m)
+			if err != nil {
+				return fmt.Errorf("unable to stat %s\n%w", m, err)
+			}
+
+			a.Logger.Bodyf("Preserving %s", rel)
+			file := filepath.Join(path, rel)
+			if info.IsDir() {
+				err = copyTree(m, file)
+			} else {
+				err = copyFile(m, file)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to preserve %s\n%w", m, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// contributeArtifact lays an artifact out in the layer.  The primary artifact, when it is a ZIP, is kept as
+// application.zip and expanded into the application root after the source code is removed, or, when it is not to be
+// exploded, must be an executable JAR and is kept as ExecutableArtifact under the artifact directory of the layer and
+// copied into the application root as-is.  All other
+// artifacts are laid out under the application directory of the layer, which is copied on top of the application root.
+// Other ZIPs are never expanded, so that they cannot overwrite the primary artifact's files, such as its manifest.
+func (a Application) contributeArtifact(layer libcnb.Layer, artifact Artifact, primary bool) error {
+	kind, err := artifact.Kind()
 	if err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to open %s\n%w", file, err)
+		return fmt.Errorf("unable to determine kind of %s\n%w", artifact.Path, err)
+	}
+
+	if primary && !a.Explode {
+		if artifact.Destination != "" || kind != ZipArtifact {
+			return fmt.Errorf("unable to launch %s with java -jar, set $BP_EXPLODE_ARTIFACT to true to expand it instead",
+				filepath.Base(artifact.Path))
+		}
+
+		c, err := NewArtifactCandidate(artifact.Path)
+		if err != nil {
+			return fmt.Errorf("unable to read manifest of %s\n%w", artifact.Path, err)
+		}
+		if c.Manifest["Main-Class"] == "" && c.Manifest["Start-Class"] == "" {
+			return fmt.Errorf("unable to launch %s with java -jar as it has no Main-Class or Start-Class, set $BP_EXPLODE_ARTIFACT to true to expand it instead",
+				filepath.Base(artifact.Path))
+		}
+
+		a.Logger.Bodyf("Keeping %s unexpanded as %s", filepath.Base(artifact.Path), ExecutableArtifact)
+		return copyFile(artifact.Path, filepath.Join(layer.Path, "artifact", ExecutableArtifact))
+	}
+
+	root := filepath.Join(layer.Path, "application")
+
+	if artifact.Destination != "" {
+		file := filepath.Join(root, artifact.Destination, filepath.Base(artifact.Path))
+		a.Logger.Bodyf("Placing %s in %s", filepath.Base(artifact.Path), artifact.Destination)
+
+		if kind == DirectoryArtifact {
+			return copyTree(artifact.Path, file)
+		}
+		return copyFile(artifact.Path, file)
+	}
+
+	switch kind {
+	case DirectoryArtifact:
+		a.Logger.Bodyf("Copying contents of %s", filepath.Base(artifact.Path))
+		return copyTree(artifact.Path, root)
+	case FileArtifact:
+		a.Logger.Bodyf("Copying %s", filepath.Base(artifact.Path))
+		return copyFile(artifact.Path, filepath.Join(root, filepath.Base(artifact.Path)))
+	case ZipArtifact:
+		if primary {
+			return copyFile(artifact.Path, filepath.Join(layer.Path, "application.zip"))
+		}
+
+		a.Logger.Bodyf("Copying %s", filepath.Base(artifact.Path))
+		return copyFile(artifact.Path, filepath.Join(root, filepath.Base(artifact.Path)))
+	}
+
+	in, err := os.Open(artifact.Path)
+	if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", artifact.Path, err)
 	}
 	defer in.Close()
 
-	if err := crush.ExtractZip(in, a.ApplicationPath, 0); err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to extract %s\n%w", file, err)
+	a.Logger.Bodyf("Expanding %s", filepath.Base(artifact.Path))
+	if kind == TarArtifact {
+		err = crush.ExtractTar(in, root, 0)
+	} else {
+		err = crush.ExtractTarGz(in, root, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to expand %s\n%w", artifact.Path, err)
 	}
 
-	return layer, nil
+	return nil
+}
+
+// writeSBOM writes CycloneDX and SPDX bills of materials for the libraries packaged in the ZIP artifacts to path.
+func (a Application) writeSBOM(path string, artifacts []Artifact) error {
+	var components []SBOMComponent
+	for _, artifact := range artifacts {
+		if kind, err := artifact.Kind(); err != nil {
+			return fmt.Errorf("unable to determine kind of %s\n%w", artifact.Path, err)
+		} else if kind != ZipArtifact {
+			continue
+		}
+
+		c, err := ScanArtifact(artifact.Path)
+		if err != nil {
+			return fmt.Errorf("unable to scan %s\n%w", artifact.Path, err)
+		}
+		components = append(components, c...)
+	}
+	a.Logger.Bodyf("Found %d libraries for bills of materials", len(components))
+
+	documents := map[string]interface{}{
+		"cyclonedx.json": NewCycloneDX(components),
+		"spdx.json":      NewSPDX(filepath.Base(artifacts[0].Path), time.Now(), components),
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("unable to create directory %s\n%w", path, err)
+	}
+
+	for name, d := range documents {
+		b, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode %s\n%w", name, err)
+		}
+
+		file := filepath.Join(path, name)
+		if err := ioutil.WriteFile(file, b, 0644); err != nil {
+			return fmt.Errorf("unable to write %s\n%w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func (a Application) logTestSummary(summary TestSummary) {
+	if summary.Reports == 0 {
+		a.Logger.Body("No test reports found")
+		return
+	}
+
+	a.Logger.Bodyf("Test results: %s", summary)
+	for _, f := range summary.Failed {
+		a.Logger.Bodyf("  Failed: %s", f)
+	}
 }
 
 func (Application) Name() string {
 	return "application"
 }
 
+// ResolveArguments returns the arguments to pass to the build system.  $BP_BUILD_ARGUMENTS replaces the default
+// arguments, and the additional arguments are always appended.
 func (a Application) ResolveArguments() ([]string, error) {
 	var err error
 	arguments := a.DefaultArguments
@@ -146,91 +438,176 @@ func (a Application) ResolveArguments() ([]string, error) {
 		}
 	}
 
+	if len(a.AdditionalArguments) > 0 {
+		arguments = append(append([]string{}, arguments...), a.AdditionalArguments...)
+	}
+
 	return arguments, nil
 }
 
+// ResolveArtifact returns the first of the built artifacts.
 func (a Application) ResolveArtifact() (string, error) {
-	pattern := a.DefaultTarget
-	if s, ok := os.LookupEnv("BP_BUILT_MODULE"); ok {
-		pattern = filepath.Join(s, pattern)
-	}
-	if s, ok := os.LookupEnv("BP_BUILT_ARTIFACT"); ok {
-		pattern = s
+	patterns, source, err := a.artifactPatterns()
+	if err != nil {
+		return "", err
 	}
 
-	file := filepath.Join(a.ApplicationPath, pattern)
-	candidates, err := filepath.Glob(file)
+	artifacts, err := a.resolveArtifacts(patterns[0], source)
 	if err != nil {
-		return "", fmt.Errorf("unable to find files with %s\n%w", pattern, err)
+		return "", err
 	}
 
-	if len(candidates) == 1 {
-		return candidates[0], nil
+	return artifacts[0].Path, nil
+}
+
+// ResolveArtifacts returns all of the built artifacts.  $BP_BUILT_ARTIFACT may contain multiple whitespace separated
+// patterns, each optionally suffixed with :<destination>.  A pattern without a destination must resolve to exactly one
+// artifact, while every file matching a pattern with a destination, other than JARs with an excluded classifier, is an
+// artifact.
+func (a Application) ResolveArtifacts() ([]Artifact, error) {
+	patterns, source, err := a.artifactPatterns()
+	if err != nil {
+		return nil, err
 	}
 
-	var artifacts []string
-	for _, c := range candidates {
-		ok, err := a.interestingFile(c)
+	var artifacts []Artifact
+	for _, p := range patterns {
+		resolved, err := a.resolveArtifacts(p, source)
 		if err != nil {
-			return "", fmt.Errorf("unable to investigate %s\n%w", c, err)
-		}
-		if ok {
-			artifacts = append(artifacts, c)
+			return nil, err
 		}
-	}
 
-	if len(artifacts) != 1 {
-		sort.Strings(artifacts)
-		return "", fmt.Errorf("unable to find built artifact (executable JAR or WAR) in %s, candidates: %s", pattern, candidates)
+		artifacts = append(artifacts, resolved...)
 	}
 
-	return artifacts[0], nil
+	return artifacts, nil
 }
 
-func (a Application) interestingEntry(f *zip.File) (bool, error) {
-	if f.Name == "WEB-INF/" && f.FileInfo().IsDir() {
-		return true, nil
+// ResolveIncludeFiles returns the glob patterns, relative to the application root, of source files and directories
+// that are restored next to the built artifact after the source code is removed.  $BP_INCLUDE_FILES is a colon
+// separated list of patterns.
+func (Application) ResolveIncludeFiles() ([]string, error) {
+	var includes []string
+
+	for _, p := range strings.Split(os.Getenv("BP_INCLUDE_FILES"), ":") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("unable to parse included file pattern %s\n%w", p, err)
+		}
+
+		includes = append(includes, p)
 	}
 
-	if f.Name == "META-INF/MANIFEST.MF" {
-		m, err := f.Open()
+	return includes, nil
+}
+
+// artifactPatterns returns the patterns of the built artifacts and a description of where they were configured.
+func (a Application) artifactPatterns() ([]string, string, error) {
+	if s, ok := os.LookupEnv("BP_BUILT_ARTIFACT"); ok {
+		patterns, err := shellwords.Parse(s)
 		if err != nil {
-			return false, fmt.Errorf("unable to open %s\n%w", f.Name, err)
+			return nil, "", fmt.Errorf("unable to parse artifacts from %s\n%w", s, err)
 		}
-		defer m.Close()
 
-		b, err := ioutil.ReadAll(m)
-		if err != nil {
-			return false, fmt.Errorf("unable to read %s\n%w", f.Name, err)
+		if len(patterns) > 0 {
+			return patterns, "$BP_BUILT_ARTIFACT", nil
+		}
+	}
+
+	if s, ok := os.LookupEnv("BP_BUILT_MODULE"); ok {
+		return []string{filepath.Join(s, a.DefaultTarget)}, "$BP_BUILT_MODULE", nil
+	}
+
+	return []string{a.DefaultTarget}, "default", nil
+}
+
+func (a Application) resolveArtifacts(pattern string, source string) ([]Artifact, error) {
+	var destination string
+	if i := strings.LastIndex(pattern, ":"); i >= 0 {
+		pattern, destination = pattern[:i], filepath.Clean(pattern[i+1:])
+
+		if filepath.IsAbs(destination) || destination == ".." || strings.HasPrefix(destination, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("destination %s must be within the application", destination)
 		}
+	}
 
-		p, err := properties.Load(b, properties.UTF8)
+	file := filepath.Join(a.ApplicationPath, pattern)
+	candidates, err := filepath.Glob(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find files with %s\n%w", pattern, err)
+	}
+
+	if destination != "" {
+		return a.resolveDestinationArtifacts(pattern, source, destination, candidates)
+	}
+
+	if len(candidates) == 1 {
+		return []Artifact{{Path: candidates[0]}}, nil
+	}
+
+	r := ArtifactResolutionError{Pattern: pattern, Source: source}
+	best := 0
+	for _, c := range candidates {
+		candidate, err := NewArtifactCandidate(c)
 		if err != nil {
-			return false, fmt.Errorf("unable to parse properties in %s\n%w", f.Name, err)
+			return nil, fmt.Errorf("unable to investigate %s\n%w", c, err)
+		}
+		if candidate.Rank > best {
+			best = candidate.Rank
 		}
+		r.Candidates = append(r.Candidates, candidate)
+	}
 
-		if _, ok := p.Get("Main-Class"); ok {
-			return true, nil
+	var artifacts []string
+	for _, c := range r.Candidates {
+		switch {
+		case c.Rank == 0:
+			a.Logger.Bodyf("Rejected %s: %s", filepath.Base(c.Path), c.Reason)
+		case c.Rank < best:
+			a.Logger.Bodyf("Rejected %s: %s, outranked by a %s", filepath.Base(c.Path), c.Reason, rankDescriptions[best])
+		default:
+			a.Logger.Bodyf("Accepted %s: %s", filepath.Base(c.Path), c.Reason)
+			artifacts = append(artifacts, c.Path)
 		}
 	}
 
-	return false, nil
+	if best == 0 || len(artifacts) != 1 {
+		a.Logger.Body(r.Report())
+		return nil, r
+	}
+
+	return []Artifact{{Path: artifacts[0]}}, nil
 }
 
-func (a Application) interestingFile(path string) (bool, error) {
-	z, err := zip.OpenReader(path)
-	if err != nil {
-		return false, fmt.Errorf("unable to open %s\n%w", path, err)
+// resolveDestinationArtifacts returns every candidate, other than JARs with an excluded classifier, as an artifact
+// placed in destination.  Such artifacts, e.g. agents, are not the application and need not be executable.
+func (a Application) resolveDestinationArtifacts(pattern string, source string, destination string, candidates []string) ([]Artifact, error) {
+	var artifacts []Artifact
+	for _, c := range candidates {
+		if e, ok := excludedClassifier(c); ok {
+			a.Logger.Bodyf("Rejected %s: excluded classifier -%s", filepath.Base(c), e)
+			continue
+		}
+
+		artifacts = append(artifacts, Artifact{Path: c, Destination: destination})
 	}
-	defer z.Close()
 
-	for _, f := range z.File {
-		if i, err := a.interestingEntry(f); err != nil {
-			return false, fmt.Errorf("unable to investigate entry %s/%s\n%w", path, f.Name, err)
-		} else if i {
-			return true, nil
+	if len(artifacts) == 0 {
+		r := ArtifactResolutionError{Pattern: pattern, Source: source}
+		for _, c := range candidates {
+			candidate, err := NewArtifactCandidate(c)
+			if err != nil {
+				return nil, fmt.Errorf("unable to investigate %s\n%w", c, err)
+			}
+			r.Candidates = append(r.Candidates, candidate)
 		}
+
+		a.Logger.Body(r.Report())
+		return nil, r
 	}
 
-	return false, nil
+	return artifacts, nil
 }
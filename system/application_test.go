@@ -17,6 +17,8 @@
 package system_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,6 +30,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/paketo-buildpacks/build-system/system"
 	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/crush"
 	"github.com/paketo-buildpacks/libpak/effect"
 	"github.com/paketo-buildpacks/libpak/effect/mocks"
 	"github.com/sclevine/spec"
@@ -85,6 +88,8 @@ func testApplication(t *testing.T, context spec.G, it spec.S) {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(layer.Cache).To(BeTrue())
+		Expect(filepath.Join(layer.Path, "sbom", "cyclonedx.json")).To(BeARegularFile())
+		Expect(filepath.Join(layer.Path, "sbom", "spdx.json")).To(BeARegularFile())
 
 		e := executor.Calls[0].Arguments[0].(effect.Execution)
 		Expect(e.Command).To(Equal("test-command"))
@@ -98,6 +103,392 @@ func testApplication(t *testing.T, context spec.G, it spec.S) {
 		Expect(filepath.Join(ctx.Application.Path, "fixture-marker")).To(BeARegularFile())
 	})
 
+	context("$BP_EXPLODE_ARTIFACT", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_EXPLODE_ARTIFACT", "false")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "pom.xml"), []byte("test-source"), 0644)).To(Succeed())
+
+			var err error
+			application, err = system.NewApplication(ctx.Application.Path, "test-command",
+				[]string{"test", "default", "arguments"}, "*.[jw]ar")
+			Expect(err).NotTo(HaveOccurred())
+			application.Executor = executor
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_EXPLODE_ARTIFACT")).To(Succeed())
+		})
+
+		it("places artifact unexpanded", func() {
+			in, err := os.Open(filepath.Join("testdata", "stub-executable.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			out, err := os.OpenFile(filepath.Join(ctx.Application.Path, "stub-executable.jar"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.Copy(out, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(application.Explode).To(BeFalse())
+			Expect(filepath.Join(layer.Path, "application.zip")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(layer.Path, "artifact", "application.jar")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "application.jar")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "stub-executable.jar")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "META-INF")).NotTo(BeAnExistingFile())
+		})
+
+		it("fails with artifact that is not executable", func() {
+			in, err := os.Open(filepath.Join("testdata", "stub-application.war"))
+			Expect(err).NotTo(HaveOccurred())
+			out, err := os.OpenFile(filepath.Join(ctx.Application.Path, "stub-application.war"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.Copy(out, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("unable to launch stub-application.war with java -jar as it has no Main-Class or Start-Class")))
+		})
+
+		it("fails with artifact that is not a ZIP", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "stub-application.jar"), []byte("test-content"), 0644)).To(Succeed())
+
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("unable to launch stub-application.jar with java -jar")))
+		})
+
+		it("invalidates cached layer", func() {
+			Expect(application.LayerContributor.ExpectedMetadata.(system.ApplicationMetadata).Explode).To(BeFalse())
+		})
+	})
+
+	context("$BP_BUILD_RUN_TESTS", func() {
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILD_RUN_TESTS")).To(Succeed())
+		})
+
+		it("invalidates cached layer", func() {
+			Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+
+			application, err := system.NewApplication(ctx.Application.Path, "test-command",
+				[]string{"test", "default", "arguments"}, "*.[jw]ar")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(application.LayerContributor.ExpectedMetadata.(system.ApplicationMetadata).RunTests).To(BeTrue())
+		})
+	})
+
+	context("$BP_INCLUDE_FILES", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_INCLUDE_FILES", "Procfile:config/*.yml")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_INCLUDE_FILES")).To(Succeed())
+		})
+
+		it("restores included files", func() {
+			in, err := os.Open(filepath.Join("testdata", "stub-application.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			out, err := os.OpenFile(filepath.Join(ctx.Application.Path, "stub-application.jar"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.Copy(out, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "config"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "Procfile"), []byte("web: java -jar app.jar"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "config", "application.yml"), []byte("test-config"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "config", "application.properties"), []byte("test-config"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "pom.xml"), []byte("test-source"), 0644)).To(Succeed())
+
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "fixture-marker")).To(BeARegularFile())
+			Expect(ioutil.ReadFile(filepath.Join(ctx.Application.Path, "Procfile"))).To(Equal([]byte("web: java -jar app.jar")))
+			Expect(ioutil.ReadFile(filepath.Join(ctx.Application.Path, "config", "application.yml"))).To(Equal([]byte("test-config")))
+			Expect(filepath.Join(ctx.Application.Path, "config", "application.properties")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "pom.xml")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "stub-application.jar")).NotTo(BeAnExistingFile())
+		})
+
+		it("resolves included files", func() {
+			Expect(application.ResolveIncludeFiles()).To(Equal([]string{"Procfile", "config/*.yml"}))
+		})
+
+		it("fails with invalid pattern", func() {
+			Expect(os.Setenv("BP_INCLUDE_FILES", "config/[")).To(Succeed())
+
+			_, err := application.ResolveIncludeFiles()
+			Expect(err).To(MatchError(ContainSubstring("unable to parse included file pattern config/[")))
+		})
+	})
+
+	context("multiple artifacts", func() {
+		it.Before(func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "stub-application.jar stub-executable.jar:agents stub-application.war")).To(Succeed())
+
+			for _, f := range []string{"stub-application.jar", "stub-application.war", "stub-executable.jar"} {
+				in, err := os.Open(filepath.Join("testdata", f))
+				Expect(err).NotTo(HaveOccurred())
+
+				out, err := os.OpenFile(filepath.Join(ctx.Application.Path, f), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = io.Copy(out, in)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(in.Close()).To(Succeed())
+				Expect(out.Close()).To(Succeed())
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILT_ARTIFACT")).To(Succeed())
+		})
+
+		it("contributes layer", func() {
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(layer.Path, "application.zip")).To(BeARegularFile())
+			Expect(filepath.Join(layer.Path, "application", "agents", "stub-executable.jar")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "stub-application.jar")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "fixture-marker")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "stub-application.war")).To(BeARegularFile())
+			Expect(filepath.Join(ctx.Application.Path, "WEB-INF")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "agents", "stub-executable.jar")).To(BeARegularFile())
+		})
+
+		it("does not expand other JARs over the primary artifact", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "stub-boot.jar stub-executable.jar")).To(Succeed())
+
+			in, err := os.Open(filepath.Join("testdata", "stub-boot.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			out, err := os.OpenFile(filepath.Join(ctx.Application.Path, "stub-boot.jar"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.Copy(out, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ioutil.ReadFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"))).
+				To(ContainSubstring("Start-Class: test.Application"))
+			Expect(filepath.Join(ctx.Application.Path, "stub-executable.jar")).To(BeARegularFile())
+		})
+
+		it("resolves artifacts", func() {
+			Expect(application.ResolveArtifacts()).To(Equal([]system.Artifact{
+				{Path: filepath.Join(ctx.Application.Path, "stub-application.jar")},
+				{Path: filepath.Join(ctx.Application.Path, "stub-executable.jar"), Destination: "agents"},
+				{Path: filepath.Join(ctx.Application.Path, "stub-application.war")},
+			}))
+			Expect(application.ResolveArtifact()).To(Equal(filepath.Join(ctx.Application.Path, "stub-application.jar")))
+		})
+
+		it("resolves every artifact matching a pattern with a destination", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "stub-application.jar agents/*.jar:agents")).To(Succeed())
+
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "agents"), 0755)).To(Succeed())
+			for _, f := range []string{"test-agent-1.jar", "test-agent-2.jar", "test-agent-2-sources.jar"} {
+				Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "agents", f), []byte{}, 0644)).To(Succeed())
+			}
+
+			Expect(application.ResolveArtifacts()).To(Equal([]system.Artifact{
+				{Path: filepath.Join(ctx.Application.Path, "stub-application.jar")},
+				{Path: filepath.Join(ctx.Application.Path, "agents", "test-agent-1.jar"), Destination: "agents"},
+				{Path: filepath.Join(ctx.Application.Path, "agents", "test-agent-2.jar"), Destination: "agents"},
+			}))
+		})
+
+		it("fails with pattern with a destination matching no artifacts", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "stub-application.jar agents/*.jar:agents")).To(Succeed())
+
+			_, err := application.ResolveArtifacts()
+			Expect(err).To(BeAssignableToTypeOf(system.ArtifactResolutionError{}))
+		})
+
+		it("fails with destination outside of application", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "stub-application.jar stub-executable.jar:../agents")).To(Succeed())
+
+			_, err := application.ResolveArtifacts()
+			Expect(err).To(MatchError("destination ../agents must be within the application"))
+		})
+	})
+
+	context("test reports", func() {
+		it.Before(func() {
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			application.TestReports = []string{filepath.Join("reports", "TEST-*.xml")}
+
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "reports"), 0755)).To(Succeed())
+
+			in, err := os.Open(filepath.Join("testdata", "stub-application.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			out, err := os.OpenFile(filepath.Join(ctx.Application.Path, "stub-application.jar"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = io.Copy(out, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(in.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+		})
+
+		it("contributes layer when tests pass", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "reports", "TEST-test.xml"),
+				[]byte(`<testsuite tests="1"><testcase classname="test" name="passes"/></testsuite>`), 0644)).To(Succeed())
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(layer.Path, "test-results", "reports", "TEST-test.xml")).To(BeARegularFile())
+		})
+
+		it("fails when tests fail", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "reports", "TEST-test.xml"),
+				[]byte(`<testsuite tests="1" failures="1"><testcase classname="test" name="fails"><failure/></testcase></testsuite>`), 0644)).To(Succeed())
+			executor.On("Execute", mock.Anything).Return(nil)
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("tests failed: 1 tests, 1 failures, 0 errors, 0 skipped")))
+		})
+
+		it("summarizes tests when build fails", func() {
+			b := bytes.NewBuffer(nil)
+			application.Logger = bard.NewLogger(b)
+
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "reports", "TEST-test.xml"),
+				[]byte(`<testsuite tests="1" failures="1"><testcase classname="test" name="fails"><failure/></testcase></testsuite>`), 0644)).To(Succeed())
+			executor.On("Execute", mock.Anything).Return(fmt.Errorf("test-error"))
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.Contribute(layer)
+			Expect(err).To(MatchError(ContainSubstring("error running build")))
+			Expect(b.String()).To(ContainSubstring("Test results: 1 tests, 1 failures, 0 errors, 0 skipped"))
+			Expect(b.String()).To(ContainSubstring("Failed: test.fails"))
+		})
+	})
+
+	context("non-archive artifacts", func() {
+		it.Before(func() {
+			application.Logger = bard.NewLogger(ioutil.Discard)
+			executor.On("Execute", mock.Anything).Return(nil)
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILT_ARTIFACT")).To(Succeed())
+		})
+
+		it("contributes contents of directory", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "build/install/test-application")).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "build", "install", "test-application", "bin"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "build", "install", "test-application", "bin", "test-application"),
+				[]byte{}, 0755)).To(Succeed())
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "build")).NotTo(BeAnExistingFile())
+			fi, err := os.Stat(filepath.Join(ctx.Application.Path, "bin", "test-application"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+
+		it("contributes expanded TAR", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "*.tar.gz")).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Layers.Path, "test-source", "test-application"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Layers.Path, "test-source", "test-application", "fixture-marker"), []byte{}, 0644)).To(Succeed())
+
+			out, err := os.Create(filepath.Join(ctx.Application.Path, "test-application.tar.gz"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crush.CreateTarGz(out, filepath.Join(ctx.Layers.Path, "test-source"))).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "test-application.tar.gz")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(ctx.Application.Path, "test-application", "fixture-marker")).To(BeARegularFile())
+		})
+
+		it("contributes executable", func() {
+			Expect(os.Setenv("BP_BUILT_ARTIFACT", "build/native-image/test-application")).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "build", "native-image"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "build", "native-image", "test-application"),
+				[]byte("test-content"), 0755)).To(Succeed())
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = application.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "build")).NotTo(BeAnExistingFile())
+			fi, err := os.Stat(filepath.Join(ctx.Application.Path, "test-application"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+	})
+
 	context("ResolveArguments", func() {
 		it("uses default arguments", func() {
 			Expect(application.ResolveArguments()).To(Equal([]string{"test", "default", "arguments"}))
@@ -117,6 +508,28 @@ func testApplication(t *testing.T, context spec.G, it spec.S) {
 				Expect(application.ResolveArguments()).To(Equal([]string{"test", "configured", "arguments"}))
 			})
 		})
+
+		context("additional arguments", func() {
+
+			it.Before(func() {
+				application.AdditionalArguments = []string{"test-additional-argument"}
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_BUILD_ARGUMENTS")).To(Succeed())
+			})
+
+			it("appends additional arguments to default arguments", func() {
+				Expect(application.ResolveArguments()).To(Equal([]string{"test", "default", "arguments", "test-additional-argument"}))
+				Expect(application.DefaultArguments).To(Equal([]string{"test", "default", "arguments"}))
+			})
+
+			it("appends additional arguments to $BP_BUILD_ARGUMENTS", func() {
+				Expect(os.Setenv("BP_BUILD_ARGUMENTS", "test configured arguments")).To(Succeed())
+
+				Expect(application.ResolveArguments()).To(Equal([]string{"test", "configured", "arguments", "test-additional-argument"}))
+			})
+		})
 	})
 
 	context("ResolveArtifact", func() {
@@ -149,6 +562,43 @@ func testApplication(t *testing.T, context spec.G, it spec.S) {
 
 		})
 
+		it("reports candidates", func() {
+			b := bytes.NewBuffer(nil)
+			application.Logger = bard.NewLogger(b)
+
+			for _, f := range []string{"stub-application.jar", "stub-application.war", "stub-executable.jar"} {
+				in, err := os.Open(filepath.Join("testdata", f))
+				Expect(err).NotTo(HaveOccurred())
+
+				out, err := os.OpenFile(filepath.Join(ctx.Application.Path, f), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = io.Copy(out, in)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(in.Close()).To(Succeed())
+				Expect(out.Close()).To(Succeed())
+			}
+
+			_, err := application.ResolveArtifact()
+
+			var r system.ArtifactResolutionError
+			Expect(errors.As(err, &r)).To(BeTrue())
+			Expect(r.Pattern).To(Equal("*.[jw]ar"))
+			Expect(r.Source).To(Equal("default"))
+			Expect(r.Candidates).To(HaveLen(3))
+			Expect(r.Candidates[0].Rank).To(Equal(0))
+			Expect(r.Candidates[0].Reason).To(Equal("no Main-Class, Start-Class, or WEB-INF/"))
+			Expect(r.Candidates[1].WAR).To(BeTrue())
+			Expect(r.Candidates[1].Rank).To(Equal(1))
+			Expect(r.Candidates[2].Manifest).To(HaveKey("Main-Class"))
+			Expect(r.Candidates[2].Rank).To(Equal(1))
+
+			Expect(b.String()).To(ContainSubstring("Unable to resolve built artifact from *.[jw]ar (default)"))
+			Expect(b.String()).To(ContainSubstring("Rejected: no Main-Class, Start-Class, or WEB-INF/"))
+			Expect(b.String()).To(ContainSubstring("WAR: true"))
+		})
+
 		it("passes with a single candidate", func() {
 			in, err := os.Open(filepath.Join("testdata", "stub-application.jar"))
 			Expect(err).NotTo(HaveOccurred())
@@ -201,6 +651,48 @@ func testApplication(t *testing.T, context spec.G, it spec.S) {
 			Expect(application.ResolveArtifact()).To(Equal(filepath.Join(ctx.Application.Path, "stub-application.war")))
 		})
 
+		it("prefers Spring Boot application", func() {
+			for _, f := range []string{"stub-boot.jar", "stub-executable.jar"} {
+				in, err := os.Open(filepath.Join("testdata", f))
+				Expect(err).NotTo(HaveOccurred())
+
+				out, err := os.OpenFile(filepath.Join(ctx.Application.Path, f), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = io.Copy(out, in)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(in.Close()).To(Succeed())
+				Expect(out.Close()).To(Succeed())
+			}
+
+			Expect(application.ResolveArtifact()).To(Equal(filepath.Join(ctx.Application.Path, "stub-boot.jar")))
+		})
+
+		it("excludes classifiers", func() {
+			b := bytes.NewBuffer(nil)
+			application.Logger = bard.NewLogger(b)
+
+			for _, f := range []string{"stub-executable.jar", "stub-executable-plain.jar", "stub-executable-sources.jar"} {
+				in, err := os.Open(filepath.Join("testdata", "stub-executable.jar"))
+				Expect(err).NotTo(HaveOccurred())
+
+				out, err := os.OpenFile(filepath.Join(ctx.Application.Path, f), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = io.Copy(out, in)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(in.Close()).To(Succeed())
+				Expect(out.Close()).To(Succeed())
+			}
+
+			Expect(application.ResolveArtifact()).To(Equal(filepath.Join(ctx.Application.Path, "stub-executable.jar")))
+			Expect(b.String()).To(ContainSubstring("Rejected stub-executable-plain.jar: excluded classifier -plain"))
+			Expect(b.String()).To(ContainSubstring("Rejected stub-executable-sources.jar: excluded classifier -sources"))
+			Expect(b.String()).To(ContainSubstring("Accepted stub-executable.jar: executable JAR or WAR"))
+		})
+
 		context("$BP_BUILT_MODULE", func() {
 
 			it.Before(func() {
@@ -0,0 +1,345 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// ArtifactKind is the kind of content of a built artifact.
+type ArtifactKind uint8
+
+const (
+	// FileArtifact is a file that is not an archive, such as a native executable.
+	FileArtifact ArtifactKind = iota
+
+	// DirectoryArtifact is a directory, such as the output of Gradle's installDist.
+	DirectoryArtifact
+
+	// TarArtifact is an uncompressed TAR archive.
+	TarArtifact
+
+	// TarGzArtifact is a GZIP compressed TAR archive.
+	TarGzArtifact
+
+	// ZipArtifact is a ZIP archive, including JARs and WARs.
+	ZipArtifact
+)
+
+// Artifact is a built artifact and where it is laid out in the application.  An artifact without a Destination is
+// expanded into the application root.  An artifact with a Destination is copied, unexpanded, into that directory
+// relative to the application root.
+type Artifact struct {
+	Path        string
+	Destination string
+}
+
+// Kind determines the kind of the artifact from its content.
+func (a Artifact) Kind() (ArtifactKind, error) {
+	s, err := os.Stat(a.Path)
+	if err != nil {
+		return FileArtifact, fmt.Errorf("unable to stat %s\n%w", a.Path, err)
+	}
+
+	if s.IsDir() {
+		return DirectoryArtifact, nil
+	}
+
+	in, err := os.Open(a.Path)
+	if err != nil {
+		return FileArtifact, fmt.Errorf("unable to open %s\n%w", a.Path, err)
+	}
+	defer in.Close()
+
+	b := make([]byte, 262)
+	n, err := io.ReadFull(in, b)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return FileArtifact, fmt.Errorf("unable to read %s\n%w", a.Path, err)
+	}
+	b = b[:n]
+
+	switch {
+	case bytes.HasPrefix(b, []byte("PK\x03\x04")), bytes.HasPrefix(b, []byte("PK\x05\x06")):
+		return ZipArtifact, nil
+	case bytes.HasPrefix(b, []byte{0x1f, 0x8b}):
+		return TarGzArtifact, nil
+	case len(b) == 262 && bytes.Equal(b[257:262], []byte("ustar")):
+		return TarArtifact, nil
+	default:
+		return FileArtifact, nil
+	}
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", source, err)
+	}
+	defer in.Close()
+
+	if err := sherpa.CopyFile(in, destination); err != nil {
+		return fmt.Errorf("unable to copy %s to %s\n%w", source, destination, err)
+	}
+
+	return nil
+}
+
+func copyTree(source string, destination string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return fmt.Errorf("unable to determine relative path of %s\n%w", path, err)
+		}
+		file := filepath.Join(destination, rel)
+
+		if info.IsDir() {
+			if err := os.MkdirAll(file, 0755); err != nil {
+				return fmt.Errorf("unable to create directory %s\n%w", file, err)
+			}
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("unable to read link %s\n%w", path, err)
+			}
+
+			if err := os.Symlink(target, file); err != nil {
+				return fmt.Errorf("unable to link %s to %s\n%w", file, target, err)
+			}
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open %s\n%w", path, err)
+		}
+		defer in.Close()
+
+		if err := sherpa.CopyFile(in, file); err != nil {
+			return fmt.Errorf("unable to copy %s to %s\n%w", path, file, err)
+		}
+
+		return nil
+	})
+}
+
+// ExplodeArtifact returns whether the built artifact should be expanded into the application root, as configured by
+// $BP_EXPLODE_ARTIFACT.  Defaults to true.
+func ExplodeArtifact() (bool, error) {
+	s, ok := os.LookupEnv("BP_EXPLODE_ARTIFACT")
+	if !ok || s == "" {
+		return true, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse $BP_EXPLODE_ARTIFACT value %s\n%w", s, err)
+	}
+
+	return b, nil
+}
+
+// excludedClassifiers are the classifiers of JARs that are built alongside, but are never, the application.
+var excludedClassifiers = []string{"javadoc", "plain", "sources", "tests"}
+
+// excludedClassifier returns the excluded classifier of the JAR at path, if it has one.
+func excludedClassifier(path string) (string, bool) {
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for _, e := range excludedClassifiers {
+		if strings.HasSuffix(stem, fmt.Sprintf("-%s", e)) {
+			return e, true
+		}
+	}
+
+	return "", false
+}
+
+// manifestAttributes are the manifest attributes that identify an application.
+var manifestAttributes = []string{"Main-Class", "Spring-Boot-Version", "Start-Class"}
+
+// rankDescriptions describe why a candidate artifact is ranked.  A higher rank is preferred and rank 0 is rejected.
+var rankDescriptions = map[int]string{
+	1: "executable JAR or WAR",
+	2: "Spring Boot application",
+}
+
+// ArtifactCandidate is a file matching the pattern of a built artifact and how it ranks as the application.
+type ArtifactCandidate struct {
+
+	// Manifest is the Main-Class, Spring-Boot-Version, and Start-Class attributes of the candidate's manifest.
+	Manifest map[string]string
+
+	// Path is the path of the candidate.
+	Path string
+
+	// Rank is the rank of the candidate.  Spring Boot applications rank above other executable JARs and WARs, and
+	// rank 0 means the candidate was rejected.
+	Rank int
+
+	// Reason describes why the candidate was ranked as it was.
+	Reason string
+
+	// WAR indicates whether the candidate contains a WEB-INF/ directory.
+	WAR bool
+}
+
+// NewArtifactCandidate inspects and ranks the candidate at path.
+func NewArtifactCandidate(path string) (ArtifactCandidate, error) {
+	c := ArtifactCandidate{Manifest: map[string]string{}, Path: path}
+
+	kind, err := Artifact{Path: path}.Kind()
+	if err != nil {
+		return ArtifactCandidate{}, fmt.Errorf("unable to determine kind of %s\n%w", path, err)
+	}
+
+	if kind == ZipArtifact {
+		if err := c.inspect(); err != nil {
+			return ArtifactCandidate{}, err
+		}
+	}
+
+	if e, ok := excludedClassifier(path); ok {
+		c.Reason = fmt.Sprintf("excluded classifier -%s", e)
+		return c, nil
+	}
+
+	switch {
+	case kind != ZipArtifact:
+		c.Reason = "not a JAR or WAR"
+	case c.Manifest["Spring-Boot-Version"] != "" || c.Manifest["Start-Class"] != "":
+		c.Rank = 2
+	case c.Manifest["Main-Class"] != "" || c.WAR:
+		c.Rank = 1
+	default:
+		c.Reason = "no Main-Class, Start-Class, or WEB-INF/"
+	}
+
+	if c.Rank > 0 {
+		c.Reason = rankDescriptions[c.Rank]
+	}
+
+	return c, nil
+}
+
+func (c *ArtifactCandidate) inspect() error {
+	z, err := zip.OpenReader(c.Path)
+	if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", c.Path, err)
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		if f.Name == "WEB-INF/" && f.FileInfo().IsDir() {
+			c.WAR = true
+		}
+
+		if f.Name != "META-INF/MANIFEST.MF" {
+			continue
+		}
+
+		m, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("unable to open %s/%s\n%w", c.Path, f.Name, err)
+		}
+
+		b, err := ioutil.ReadAll(m)
+		m.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read %s/%s\n%w", c.Path, f.Name, err)
+		}
+
+		p, err := properties.Load(b, properties.UTF8)
+		if err != nil {
+			return fmt.Errorf("unable to parse properties in %s/%s\n%w", c.Path, f.Name, err)
+		}
+
+		for _, a := range manifestAttributes {
+			if v, ok := p.Get(a); ok {
+				c.Manifest[a] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+// ArtifactResolutionError is returned when a pattern does not resolve to exactly one built artifact.
+type ArtifactResolutionError struct {
+
+	// Candidates are the files matching the pattern.
+	Candidates []ArtifactCandidate
+
+	// Pattern is the pattern of the built artifact.
+	Pattern string
+
+	// Source describes where the pattern was configured: default, $BP_BUILT_MODULE, or $BP_BUILT_ARTIFACT.
+	Source string
+}
+
+func (e ArtifactResolutionError) Error() string {
+	paths := []string{}
+	for _, c := range e.Candidates {
+		paths = append(paths, c.Path)
+	}
+
+	return fmt.Sprintf("unable to find built artifact (executable JAR or WAR) in %s, candidates: %s", e.Pattern, paths)
+}
+
+// Report returns a multi-line description of the resolution, suitable for logging.
+func (e ArtifactResolutionError) Report() string {
+	b := &strings.Builder{}
+
+	_, _ = fmt.Fprintf(b, "Unable to resolve built artifact from %s (%s)", e.Pattern, e.Source)
+	if len(e.Candidates) == 0 {
+		_, _ = fmt.Fprint(b, "\n  No candidates")
+	}
+
+	for _, c := range e.Candidates {
+		_, _ = fmt.Fprintf(b, "\n  %s", c.Path)
+		if c.Rank == 0 {
+			_, _ = fmt.Fprintf(b, "\n    Rejected: %s", c.Reason)
+		} else {
+			_, _ = fmt.Fprintf(b, "\n    Rank %d: %s", c.Rank, c.Reason)
+		}
+		_, _ = fmt.Fprintf(b, "\n    WAR: %t", c.WAR)
+
+		for _, a := range manifestAttributes {
+			if v, ok := c.Manifest[a]; ok {
+				_, _ = fmt.Fprintf(b, "\n    %s: %s", a, v)
+			}
+		}
+	}
+
+	return b.String()
+}
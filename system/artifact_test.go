@@ -0,0 +1,164 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/paketo-buildpacks/libpak/crush"
+	"github.com/sclevine/spec"
+)
+
+func testArtifact(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "artifact")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("identifies directories", func() {
+		Expect(system.Artifact{Path: path}.Kind()).To(Equal(system.DirectoryArtifact))
+	})
+
+	it("identifies ZIPs", func() {
+		Expect(system.Artifact{Path: filepath.Join("testdata", "stub-application.jar")}.Kind()).To(Equal(system.ZipArtifact))
+		Expect(system.Artifact{Path: filepath.Join("testdata", "stub-application.war")}.Kind()).To(Equal(system.ZipArtifact))
+	})
+
+	it("identifies TARs", func() {
+		out, err := os.Create(filepath.Join(path, "test.tar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crush.CreateTar(out, "testdata")).To(Succeed())
+		Expect(out.Close()).To(Succeed())
+
+		Expect(system.Artifact{Path: filepath.Join(path, "test.tar")}.Kind()).To(Equal(system.TarArtifact))
+	})
+
+	it("identifies GZIP compressed TARs", func() {
+		out, err := os.Create(filepath.Join(path, "test.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crush.CreateTarGz(out, "testdata")).To(Succeed())
+		Expect(out.Close()).To(Succeed())
+
+		Expect(system.Artifact{Path: filepath.Join(path, "test.tgz")}.Kind()).To(Equal(system.TarGzArtifact))
+	})
+
+	it("identifies other files", func() {
+		Expect(ioutil.WriteFile(filepath.Join(path, "test-executable"), []byte("test-content"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "test-empty"), []byte{}, 0644)).To(Succeed())
+
+		Expect(system.Artifact{Path: filepath.Join(path, "test-executable")}.Kind()).To(Equal(system.FileArtifact))
+		Expect(system.Artifact{Path: filepath.Join(path, "test-empty")}.Kind()).To(Equal(system.FileArtifact))
+	})
+
+	context("ExplodeArtifact", func() {
+		it.After(func() {
+			Expect(os.Unsetenv("BP_EXPLODE_ARTIFACT")).To(Succeed())
+		})
+
+		it("defaults to true", func() {
+			Expect(system.ExplodeArtifact()).To(BeTrue())
+		})
+
+		it("parses $BP_EXPLODE_ARTIFACT", func() {
+			Expect(os.Setenv("BP_EXPLODE_ARTIFACT", "false")).To(Succeed())
+			Expect(system.ExplodeArtifact()).To(BeFalse())
+		})
+
+		it("fails with invalid $BP_EXPLODE_ARTIFACT", func() {
+			Expect(os.Setenv("BP_EXPLODE_ARTIFACT", "test-value")).To(Succeed())
+
+			_, err := system.ExplodeArtifact()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("NewArtifactCandidate", func() {
+		it("ranks Spring Boot applications", func() {
+			c, err := system.NewArtifactCandidate(filepath.Join("testdata", "stub-boot.jar"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(2))
+			Expect(c.Reason).To(Equal("Spring Boot application"))
+			Expect(c.Manifest).To(HaveKey("Spring-Boot-Version"))
+		})
+
+		it("ranks executable JARs", func() {
+			c, err := system.NewArtifactCandidate(filepath.Join("testdata", "stub-executable.jar"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(1))
+			Expect(c.Reason).To(Equal("executable JAR or WAR"))
+			Expect(c.WAR).To(BeFalse())
+		})
+
+		it("ranks WARs", func() {
+			c, err := system.NewArtifactCandidate(filepath.Join("testdata", "stub-application.war"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(1))
+			Expect(c.WAR).To(BeTrue())
+		})
+
+		it("rejects non-executable JARs", func() {
+			c, err := system.NewArtifactCandidate(filepath.Join("testdata", "stub-application.jar"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(0))
+			Expect(c.Reason).To(Equal("no Main-Class, Start-Class, or WEB-INF/"))
+		})
+
+		it("rejects excluded classifiers", func() {
+			in, err := ioutil.ReadFile(filepath.Join("testdata", "stub-executable.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(path, "stub-executable-sources.jar"), in, 0644)).To(Succeed())
+
+			c, err := system.NewArtifactCandidate(filepath.Join(path, "stub-executable-sources.jar"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(0))
+			Expect(c.Reason).To(Equal("excluded classifier -sources"))
+			Expect(c.Manifest).To(HaveKey("Main-Class"))
+		})
+
+		it("rejects other files", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "test-executable"), []byte("test-content"), 0755)).To(Succeed())
+
+			c, err := system.NewArtifactCandidate(filepath.Join(path, "test-executable"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Rank).To(Equal(0))
+			Expect(c.Reason).To(Equal("not a JAR or WAR"))
+		})
+	})
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+)
+
+// ResolveBinding returns the single binding of kind in platform.  Returns false if there is no such binding.
+func ResolveBinding(platform libcnb.Platform, kind string) (libcnb.Binding, bool, error) {
+	br := libpak.BindingResolver{Bindings: platform.Bindings}
+
+	b, ok, err := br.Resolve(kind, "")
+	if err != nil {
+		return libcnb.Binding{}, false, fmt.Errorf("unable to resolve binding %s\n%w", kind, err)
+	}
+
+	return b, ok, nil
+}
+
+// SecretPath returns the path of the file holding the secret key of binding.  Returns false if binding does not
+// contain key.
+func SecretPath(platform libcnb.Platform, binding libcnb.Binding, key string) (string, bool) {
+	if _, ok := binding.Secret[key]; !ok {
+		return "", false
+	}
+
+	return filepath.Join(platform.Path, "bindings", binding.Name, "secret", key), true
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/sclevine/spec"
+)
+
+func testBinding(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		platform = libcnb.Platform{Path: "/platform"}
+	)
+
+	context("ResolveBinding", func() {
+		it("returns false without binding", func() {
+			_, ok, err := system.ResolveBinding(platform, "test-kind")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns binding of kind", func() {
+			platform.Bindings = libcnb.Bindings{
+				{Name: "test-binding-1", Metadata: map[string]string{libcnb.BindingKind: "other-kind"}},
+				{Name: "test-binding-2", Metadata: map[string]string{libcnb.BindingKind: "test-kind"}},
+			}
+
+			b, ok, err := system.ResolveBinding(platform, "test-kind")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(b.Name).To(Equal("test-binding-2"))
+		})
+
+		it("fails with multiple bindings of kind", func() {
+			platform.Bindings = libcnb.Bindings{
+				{Name: "test-binding-1", Metadata: map[string]string{libcnb.BindingKind: "test-kind"}},
+				{Name: "test-binding-2", Metadata: map[string]string{libcnb.BindingKind: "test-kind"}},
+			}
+
+			_, _, err := system.ResolveBinding(platform, "test-kind")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("SecretPath", func() {
+		binding := libcnb.Binding{Name: "test-binding", Secret: map[string]string{"test-key": "test-value"}}
+
+		it("returns path of secret", func() {
+			path, ok := system.SecretPath(platform, binding, "test-key")
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal("/platform/bindings/test-binding/secret/test-key"))
+		})
+
+		it("returns false without secret", func() {
+			_, ok := system.SecretPath(platform, binding, "other-key")
+			Expect(ok).To(BeFalse())
+		})
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
 )
@@ -46,6 +47,11 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	dc := libpak.NewDependencyCache(context.Buildpack)
 	dc.Logger = b.Logger
 
+	runTests, err := RunTests()
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to determine whether to run tests\n%w", err)
+	}
+
 	for _, s := range b.Systems {
 		if ok, err := s.Participate(pr); err != nil {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to determine participation\n%w", err)
@@ -55,40 +61,191 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 
 		b.Logger.Body(bard.FormatUserConfig("BP_BUILD_ARGUMENTS", "the arguments passed to the build system",
 			strings.Join(s.DefaultArguments(), " ")))
+		b.Logger.Body(bard.FormatUserConfig("BP_BUILD_EXCLUDE", "the source files ignored when deciding whether to rebuild", "<NONE>"))
+		b.Logger.Body(bard.FormatUserConfig("BP_BUILD_RUN_TESTS", "whether to run tests during the build", "false"))
+		b.Logger.Body(bard.FormatUserConfig("BP_BUILD_USE_GITIGNORE", "whether .gitignore lists the source files ignored when deciding whether to rebuild", "false"))
 		b.Logger.Body(bard.FormatUserConfig("BP_BUILT_MODULE", "the module to find application artifact in", "<ROOT>"))
 		b.Logger.Body(bard.FormatUserConfig("BP_BUILT_ARTIFACT", "the built application artifact", s.DefaultTarget()))
+		b.Logger.Body(bard.FormatUserConfig("BP_CACHE_MAX_SIZE", "the size beyond which least recently used cache entries are pruned", "<UNLIMITED>"))
+		b.Logger.Body(bard.FormatUserConfig("BP_CACHE_MAX_UNUSED_BUILDS", "the number of builds after which unused cache entries are pruned", "5"))
+		b.Logger.Body(bard.FormatUserConfig("BP_EXPLODE_ARTIFACT", "whether to expand the built artifact into the application root", "true"))
+		b.Logger.Body(bard.FormatUserConfig("BP_INCLUDE_FILES", "the source files to keep alongside the built artifact", "<NONE>"))
+
+		entry := libcnb.BuildpackPlanEntry{Name: "build-system", Metadata: map[string]interface{}{}}
 
 		var command string
-		wrapper := filepath.Join(context.Application.Path, s.Wrapper())
-		if _, err := os.Stat(wrapper); os.IsNotExist(err) {
+		var seed libcnb.LayerContributor
+		for _, w := range s.Wrappers() {
+			wrapper := filepath.Join(context.Application.Path, w)
+			if _, err := os.Stat(wrapper); err == nil {
+				command = wrapper
+				break
+			} else if !os.IsNotExist(err) {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to stat %s\n%w", wrapper, err)
+			}
+		}
+
+		if command != "" {
+			entry.Metadata["wrapper"] = true
+
+			if p, ok := s.(WrapperDistributionProvider); ok {
+				d, ok, err := p.WrapperDistribution(context.Application.Path)
+				if err != nil {
+					return libcnb.BuildResult{}, fmt.Errorf("unable to determine wrapper distribution\n%w", err)
+				} else if ok {
+					entry.Version = d.Version
+					entry.Metadata["uri"] = d.DistributionURL
+					entry.Metadata["sha256"] = d.DistributionSHA256
+
+					if seed, _, err = p.WrapperDistributionLayer(context.Application.Path, d, dr, dc); err != nil {
+						return libcnb.BuildResult{}, fmt.Errorf("unable to create wrapper distribution layer\n%w", err)
+					}
+
+					// The wrapper rarely declares a checksum, so a seeded distribution is recorded as the dependency.
+					if l, ok := seed.(WrapperDistributionLayer); ok {
+						entry.Metadata["uri"] = l.Dependency.URI
+						entry.Metadata["sha256"] = l.Dependency.SHA256
+					}
+				}
+			}
+		} else {
+			entry.Metadata["wrapper"] = false
 			command = s.Distribution(context.Layers.Path)
 
-			layer, err := s.DistributionLayer(dr, dc, &result.Plan)
+			n := len(result.Plan.Entries)
+			layer, err := s.DistributionLayer(context.Application.Path, dr, dc, &result.Plan)
 			if err != nil {
 				return libcnb.BuildResult{}, fmt.Errorf("unable to create distribution layer\n%w", err)
 			}
 			result.Layers = append(result.Layers, layer)
-		} else if err != nil {
-			return libcnb.BuildResult{}, fmt.Errorf("unable to stat %s\n%w", wrapper, err)
-		} else {
-			command = wrapper
+
+			// The distribution dependency is recorded in the plan as it is resolved.
+			if len(result.Plan.Entries) > n {
+				e := result.Plan.Entries[len(result.Plan.Entries)-1]
+				entry.Version = e.Version
+				entry.Metadata["uri"] = e.Metadata["uri"]
+				entry.Metadata["sha256"] = e.Metadata["sha256"]
+			}
 		}
+		entry.Metadata["command"] = filepath.Base(command)
 
-		cache, err := s.CachePath()
+		caches, err := s.Caches(context.Application.Path)
 		if err != nil {
-			return libcnb.BuildResult{}, fmt.Errorf("unable to determine cache location\n%w", err)
+			return libcnb.BuildResult{}, fmt.Errorf("unable to determine cache locations\n%w", err)
+		}
+		var pruned []Cache
+		for i, c := range caches {
+			if i > 0 {
+				c.Qualifier = strings.TrimPrefix(filepath.Base(c.Path), ".")
+			}
+			c.Logger = b.Logger
+			result.Layers = append(result.Layers, c)
+
+			if len(c.PruneRoots) > 0 {
+				pruned = append(pruned, c)
+			}
+		}
+
+		// The wrapper distribution is seeded once the caches it is typically within are linked.
+		if seed != nil {
+			result.Layers = append(result.Layers, seed)
+		}
+
+		if p, ok := s.(LayersProvider); ok {
+			layers, err := p.AdditionalLayers(context)
+			if err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to create additional layers\n%w", err)
+			}
+			result.Layers = append(result.Layers, layers...)
+		}
+
+		var additional []string
+		if p, ok := s.(ArgumentsProvider); ok {
+			if additional, err = p.AdditionalArguments(context); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to determine additional arguments\n%w", err)
+			}
 		}
-		c := NewCache(cache)
-		c.Logger = b.Logger
-		result.Layers = append(result.Layers, c)
 
 		a, err := NewApplication(context.Application.Path, command, s.DefaultArguments(), s.DefaultTarget())
 		if err != nil {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to create application layer\n%w", err)
 		}
+		a.AdditionalArguments = additional
 		a.Logger = b.Logger
+		if p, ok := s.(TestReportsProvider); ok && runTests {
+			a.TestReports = p.TestReports()
+		}
+
+		// An unexpanded artifact is launched with java -jar.  The application layer fails unless it is executable.
+		if !a.Explode {
+			result.Processes = append(result.Processes,
+				libcnb.Process{Type: "executable-jar", Command: "java", Arguments: []string{"-jar", ExecutableArtifact}},
+				libcnb.Process{Type: "web", Command: "java", Arguments: []string{"-jar", ExecutableArtifact}},
+			)
+		}
 		result.Layers = append(result.Layers, a)
+
+		if entry.Metadata["arguments"], err = a.ResolveArguments(); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to resolve arguments\n%w", err)
+		}
+		if jdk, ok, err := JDK(); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to determine JDK\n%w", err)
+		} else if ok {
+			entry.Metadata["jdk"] = jdk
+		}
+		result.Plan.Entries = append(result.Plan.Entries, entry)
+
+		// The counts of the test results are recorded in the plan once the tests have run.
+		if runTests {
+			r := NewTestResults(filepath.Join(context.Layers.Path, a.Name(), "test-results"))
+			r.Logger = b.Logger
+			r.PlanMetadata = map[string]interface{}{}
+			result.Layers = append(result.Layers, r)
+			result.Plan.Entries = append(result.Plan.Entries, libcnb.BuildpackPlanEntry{Name: "test-results", Metadata: r.PlanMetadata})
+		}
+
+		sbom := NewSBOM(filepath.Join(context.Layers.Path, a.Name(), "sbom"))
+		sbom.Logger = b.Logger
+		result.Layers = append(result.Layers, sbom)
+
+		// Caches are pruned last, once the application has been built.
+		if len(pruned) > 0 {
+			p, err := NewCachePruner(context.Layers.Path, pruned)
+			if err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to create cache pruner\n%w", err)
+			}
+			p.Logger = b.Logger
+			result.Layers = append(result.Layers, p)
+		}
 	}
 
 	return result, nil
 }
+
+// JDK returns the location and version of the JDK in $JAVA_HOME.  The version is read from $JAVA_HOME/release and is
+// omitted if that file does not exist.  Returns false if $JAVA_HOME is not set.
+func JDK() (map[string]interface{}, bool, error) {
+	home, ok := os.LookupEnv("JAVA_HOME")
+	if !ok {
+		return nil, false, nil
+	}
+	jdk := map[string]interface{}{"path": home}
+
+	file := filepath.Join(home, "release")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return jdk, true, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("unable to stat %s\n%w", file, err)
+	}
+
+	p, err := properties.LoadFile(file, properties.UTF8)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read properties from %s\n%w", file, err)
+	}
+
+	if v, ok := p.Get("JAVA_VERSION"); ok {
+		jdk["version"] = strings.Trim(v, `"`)
+	}
+
+	return jdk, true, nil
+}
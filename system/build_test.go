@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/paketo-buildpacks/build-system/system"
 	sMocks "github.com/paketo-buildpacks/build-system/system/mocks"
+	"github.com/paketo-buildpacks/libpak"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/mock"
 )
@@ -35,9 +36,15 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 	var (
 		Expect = NewWithT(t).Expect
 
+		arguments    *sMocks.ArgumentsProvider
 		build        system.Build
 		ctx          libcnb.BuildContext
 		distribution *lMocks.LayerContributor
+		layers       *sMocks.LayersProvider
+		reports      *sMocks.TestReportsProvider
+		seed         system.WrapperDistributionLayer // declared before system, which shadows the package
+		wrapper      system.WrapperDistribution      // declared before system, which shadows the package
+		wrappers     *sMocks.WrapperDistributionProvider
 		system       *sMocks.System
 	)
 
@@ -50,8 +57,13 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		distribution = &lMocks.LayerContributor{}
 		distribution.On("Name").Return("distribution")
 
+		arguments = &sMocks.ArgumentsProvider{}
+		layers = &sMocks.LayersProvider{}
+		reports = &sMocks.TestReportsProvider{}
+		wrappers = &sMocks.WrapperDistributionProvider{}
+
 		system = &sMocks.System{}
-		build.Systems = append(build.Systems, system)
+		build.Systems = append(build.Systems, providers{system, arguments, layers, reports, wrappers})
 	})
 
 	it.After(func() {
@@ -68,35 +80,398 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
 
 		system.On("Participate", mock.Anything).Return(true, nil)
-		system.On("Wrapper").Return("test-wrapper")
-		system.On("CachePath").Return("test-cache-path", nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(3))
+		Expect(result.Layers[0].Name()).To(Equal("cache"))
+		Expect(result.Layers[1].Name()).To(Equal("application"))
+		Expect(result.Layers[2].Name()).To(Equal("sbom"))
+	})
+
+	it("runs first wrapper that exists", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-other-wrapper", "test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Plan.Entries[0].Metadata).To(HaveKeyWithValue("command", "test-wrapper"))
+		Expect(result.Plan.Entries[0].Metadata).To(HaveKeyWithValue("wrapper", true))
+	})
+
+	it("contributes system without optional interfaces", func() {
+		Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+		defer os.Unsetenv("BP_BUILD_RUN_TESTS")
+
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		build.Systems = append(build.Systems[:0], system)
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("cache"))
+		Expect(result.Layers[1].Name()).To(Equal("application"))
+		Expect(result.Layers[2].Name()).To(Equal("test-results"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+		Expect(result.Plan.Entries[0].Metadata).To(HaveKeyWithValue("wrapper", true))
+		Expect(result.Plan.Entries[0].Version).To(BeEmpty())
+	})
+
+	it("contributes wrapper distribution layer", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, true, nil)
+		wrappers.On("WrapperDistributionLayer", mock.Anything, wrapper, mock.Anything, mock.Anything).Return(distribution, true, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
 		system.On("DefaultArguments").Return([]string{"test-argument"})
 		system.On("DefaultTarget").Return("test-target")
 
 		result, err := build.Build(ctx)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(result.Layers).To(HaveLen(2))
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("cache"))
+		Expect(result.Layers[1].Name()).To(Equal("distribution"))
+		Expect(result.Layers[2].Name()).To(Equal("application"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+	})
+
+	it("contributes cache pruner", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(mavenCaches(filepath.Join("test-home", ".m2")), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
 		Expect(result.Layers[0].Name()).To(Equal("cache"))
 		Expect(result.Layers[1].Name()).To(Equal("application"))
+		Expect(result.Layers[2].Name()).To(Equal("sbom"))
+		Expect(result.Layers[3].Name()).To(Equal("cache-usage"))
 	})
 
 	it("contributes system with distribution", func() {
 		system.On("Participate", mock.Anything).Return(true, nil)
-		system.On("Wrapper").Return("test-wrapper")
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
 		system.On("Distribution", mock.Anything).Return("test-distribution")
-		system.On("DistributionLayer", mock.Anything, mock.Anything, mock.Anything).Return(distribution, nil)
-		system.On("CachePath").Return("test-cache-path", nil)
+		system.On("DistributionLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(distribution, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
 		system.On("DefaultArguments").Return([]string{"test-argument"})
 		system.On("DefaultTarget").Return("test-target")
 
 		result, err := build.Build(ctx)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(result.Layers).To(HaveLen(3))
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("distribution"))
+		Expect(result.Layers[1].Name()).To(Equal("cache"))
+		Expect(result.Layers[2].Name()).To(Equal("application"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+	})
+
+	it("contributes system with distribution when it has no wrapper", func() {
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return(nil)
+		system.On("Distribution", mock.Anything).Return("test-distribution")
+		system.On("DistributionLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(distribution, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
 		Expect(result.Layers[0].Name()).To(Equal("distribution"))
 		Expect(result.Layers[1].Name()).To(Equal("cache"))
 		Expect(result.Layers[2].Name()).To(Equal("application"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
 	})
 
+	it("contributes multiple caches", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path", ".test-other-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("cache"))
+		Expect(result.Layers[1].Name()).To(Equal("cache-test-other-cache-path"))
+		Expect(result.Layers[2].Name()).To(Equal("application"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+	})
+
+	it("contributes additional layers", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		additional := &lMocks.LayerContributor{}
+		additional.On("Name").Return("test-additional-layer")
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return([]libcnb.LayerContributor{additional}, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[0].Name()).To(Equal("cache"))
+		Expect(result.Layers[1].Name()).To(Equal("test-additional-layer"))
+		Expect(result.Layers[2].Name()).To(Equal("application"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+	})
+
+	it("contributes test reports with $BP_BUILD_RUN_TESTS", func() {
+		Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+		defer os.Unsetenv("BP_BUILD_RUN_TESTS")
+
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+		reports.On("TestReports").Return([]string{"test-reports"})
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		reports.AssertCalled(t, "TestReports")
+		Expect(result.Layers).To(HaveLen(4))
+		Expect(result.Layers[2].Name()).To(Equal("test-results"))
+		Expect(result.Layers[3].Name()).To(Equal("sbom"))
+		Expect(result.Plan.Entries).To(HaveLen(2))
+		Expect(result.Plan.Entries[1].Name).To(Equal("test-results"))
+	})
+
+	context("build system plan entry", func() {
+		it.Before(func() {
+			system.On("Participate", mock.Anything).Return(true, nil)
+			arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+			layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+			system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+			system.On("DefaultArguments").Return([]string{"test-argument"})
+			system.On("DefaultTarget").Return("test-target")
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("JAVA_HOME")).To(Succeed())
+		})
+
+		it("records wrapper distribution", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+			wrapper.DistributionURL = "https://localhost/test-distribution-1.1.1.zip"
+			wrapper.DistributionSHA256 = "test-sha256"
+			wrapper.Version = "1.1.1"
+			system.On("Wrappers").Return([]string{"test-wrapper"})
+			wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, true, nil)
+			wrappers.On("WrapperDistributionLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, false, nil)
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Plan.Entries).To(ConsistOf(libcnb.BuildpackPlanEntry{
+				Name:    "build-system",
+				Version: "1.1.1",
+				Metadata: map[string]interface{}{
+					"arguments": []string{"test-argument"},
+					"command":   "test-wrapper",
+					"sha256":    "test-sha256",
+					"uri":       "https://localhost/test-distribution-1.1.1.zip",
+					"wrapper":   true,
+				},
+			}))
+		})
+
+		it("records seeded wrapper distribution", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+			wrapper.DistributionURL = "https://localhost/test-distribution-1.1.1.zip"
+			wrapper.Version = "1.1.1"
+			seed.Dependency = libpak.BuildpackDependency{
+				ID:      "test-id",
+				Version: "1.1.1",
+				URI:     "https://localhost/test-dependency-1.1.1.zip",
+				SHA256:  "test-dependency-sha256",
+			}
+			seed.LayerName = "test-wrapper-distribution"
+			system.On("Wrappers").Return([]string{"test-wrapper"})
+			wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, true, nil)
+			wrappers.On("WrapperDistributionLayer", mock.Anything, wrapper, mock.Anything, mock.Anything).Return(seed, true, nil)
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Plan.Entries[0].Version).To(Equal("1.1.1"))
+			Expect(result.Plan.Entries[0].Metadata).To(HaveKeyWithValue("uri", "https://localhost/test-dependency-1.1.1.zip"))
+			Expect(result.Plan.Entries[0].Metadata).To(HaveKeyWithValue("sha256", "test-dependency-sha256"))
+		})
+
+		it("records distribution and JDK", func() {
+			Expect(os.Setenv("JAVA_HOME", ctx.Application.Path)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "release"), []byte(`JAVA_VERSION="11.0.8"`), 0644)).To(Succeed())
+
+			system.On("Wrappers").Return(nil)
+			system.On("Distribution", mock.Anything).Return("test-distribution")
+			system.On("DistributionLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					plan := args.Get(3).(*libcnb.BuildpackPlan)
+					plan.Entries = append(plan.Entries, libcnb.BuildpackPlanEntry{
+						Name:     "test-distribution",
+						Version:  "2.2.2",
+						Metadata: map[string]interface{}{"uri": "test-uri", "sha256": "test-sha256"},
+					})
+				}).
+				Return(distribution, nil)
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Plan.Entries).To(HaveLen(2))
+			Expect(result.Plan.Entries[1]).To(Equal(libcnb.BuildpackPlanEntry{
+				Name:    "build-system",
+				Version: "2.2.2",
+				Metadata: map[string]interface{}{
+					"arguments": []string{"test-argument"},
+					"command":   "test-distribution",
+					"jdk":       map[string]interface{}{"path": ctx.Application.Path, "version": "11.0.8"},
+					"sha256":    "test-sha256",
+					"uri":       "test-uri",
+					"wrapper":   false,
+				},
+			}))
+		})
+	})
+
+	it("launches unexpanded artifact", func() {
+		Expect(os.Setenv("BP_EXPLODE_ARTIFACT", "false")).To(Succeed())
+		defer os.Unsetenv("BP_EXPLODE_ARTIFACT")
+
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return(nil, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Plan.Entries).To(HaveLen(1))
+		Expect(result.Processes).To(Equal([]libcnb.Process{
+			{Type: "executable-jar", Command: "java", Arguments: []string{"-jar", "application.jar"}},
+			{Type: "web", Command: "java", Arguments: []string{"-jar", "application.jar"}},
+		}))
+	})
+
+	it("contributes additional arguments", func() {
+		Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-wrapper"), []byte(""), 0644))
+
+		system.On("Participate", mock.Anything).Return(true, nil)
+		arguments.On("AdditionalArguments", mock.Anything).Return([]string{"test-additional-argument"}, nil)
+		layers.On("AdditionalLayers", mock.Anything).Return(nil, nil)
+		system.On("Wrappers").Return([]string{"test-wrapper"})
+		wrappers.On("WrapperDistribution", mock.Anything).Return(wrapper, false, nil)
+		system.On("Caches", mock.Anything).Return(caches("test-cache-path"), nil)
+		system.On("DefaultArguments").Return([]string{"test-argument"})
+		system.On("DefaultTarget").Return("test-target")
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		application, ok := result.Layers[1].(interface{ ResolveArguments() ([]string, error) })
+		Expect(ok).To(BeTrue())
+		Expect(application.ResolveArguments()).To(Equal([]string{"test-argument", "test-additional-argument"}))
+	})
+
+}
+
+func caches(paths ...string) []system.Cache {
+	var c []system.Cache
+	for _, p := range paths {
+		c = append(c, system.NewCache(p))
+	}
+	return c
+}
+
+func mavenCaches(paths ...string) []system.Cache {
+	var c []system.Cache
+	for _, p := range paths {
+		c = append(c, system.NewMavenCache(p))
+	}
+	return c
+}
+
+// providers is a System that also implements each of the optional interfaces.
+type providers struct {
+	*sMocks.System
+	*sMocks.ArgumentsProvider
+	*sMocks.LayersProvider
+	*sMocks.TestReportsProvider
+	*sMocks.WrapperDistributionProvider
 }
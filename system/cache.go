@@ -18,44 +18,178 @@ package system
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/buildpacks/libcnb"
 	"github.com/paketo-buildpacks/libpak/bard"
 )
 
+// Cache is a location the build system caches downloads in, such as ~/.m2, that is linked to a layer.  Links are other
+// locations the build system is configured to use, keyed by path, for directories, relative to the cache, within it.
 type Cache struct {
-	Logger bard.Logger
-	Path   string
+	Links          map[string]string
+	Logger         bard.Logger
+	Path           string
+	PersistedPaths []string
+	PruneRoots     []string
+	Qualifier      string
 }
 
+// NewCache creates a new Cache for path that is persisted in its entirety and never pruned.
 func NewCache(path string) Cache {
 	return Cache{Path: path}
 }
 
+// NewGradleCache creates a new Cache for the Gradle user home at path.  Only the downloaded dependencies, which are
+// pruned individually, and the wrapper distributions are persisted.  Everything else, such as daemon logs, native
+// libraries, and transformed artifacts, is discarded.
+func NewGradleCache(path string) Cache {
+	return Cache{
+		Path:           path,
+		PersistedPaths: []string{filepath.Join("caches", "modules-2"), filepath.Join("wrapper", "dists")},
+		PruneRoots:     []string{filepath.Join("caches", "modules-2", "files-2.1")},
+	}
+}
+
+// NewMavenCache creates a new Cache for the Maven user home at path, such as ~/.m2, whose local repository artifacts
+// are pruned individually.
+func NewMavenCache(path string) Cache {
+	return Cache{Path: path, PruneRoots: []string{"repository"}}
+}
+
+// Contribute links the cache, and each of its Links, to the layer.  If the cache has PersistedPaths, the cache itself
+// is an ephemeral directory and only those paths are linked to the layer, and anything else in the layer, left by
+// previous builds, is removed.
 func (c Cache) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 	if err := os.MkdirAll(layer.Path, 0755); err != nil {
 		return libcnb.Layer{}, fmt.Errorf("unable to create layer directory %s\n%w", layer.Path, err)
 	}
 
-	file := filepath.Dir(c.Path)
+	if len(c.PersistedPaths) == 0 {
+		if err := c.link(layer.Path, c.Path); err != nil {
+			return libcnb.Layer{}, err
+		}
+	} else {
+		if err := c.discard(layer.Path, ""); err != nil {
+			return libcnb.Layer{}, err
+		}
+
+		for _, p := range c.PersistedPaths {
+			file := filepath.Join(layer.Path, p)
+			if err := os.MkdirAll(file, 0755); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", file, err)
+			}
+
+			if err := c.link(file, filepath.Join(c.Path, p)); err != nil {
+				return libcnb.Layer{}, err
+			}
+		}
+	}
+
+	for path, rel := range c.Links {
+		file := filepath.Join(layer.Path, rel)
+		if err := os.MkdirAll(file, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", file, err)
+		}
+
+		if err := c.link(file, path); err != nil {
+			return libcnb.Layer{}, err
+		}
+	}
+
+	for _, r := range c.PruneRoots {
+		if err := resetAccessTimes(filepath.Join(layer.Path, r)); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to reset access times in %s\n%w", r, err)
+		}
+	}
+
+	layer.Cache = true
+	return layer, nil
+}
+
+func (c Cache) Name() string {
+	if c.Qualifier == "" {
+		return "cache"
+	}
+
+	return fmt.Sprintf("cache-%s", c.Qualifier)
+}
+
+func (c Cache) link(source string, path string) error {
+	file := filepath.Dir(path)
 	if err := os.MkdirAll(file, 0755); err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", file, err)
+		return fmt.Errorf("unable to create directory %s\n%w", file, err)
 	}
 
-	if err := os.Symlink(layer.Path, c.Path); os.IsExist(err) {
+	if err := os.Symlink(source, path); os.IsExist(err) {
 		c.Logger.Body("Cache already exists")
 	} else if err != nil {
-		return libcnb.Layer{}, fmt.Errorf("unable to link cache from %s to %s\n%w", layer.Path, c.Path, err)
+		return fmt.Errorf("unable to link cache from %s to %s\n%w", source, path, err)
 	} else {
-		c.Logger.Bodyf("Creating cache directory %s", c.Path)
+		c.Logger.Bodyf("Creating cache directory %s", path)
 	}
 
-	layer.Cache = true
-	return layer, nil
+	return nil
 }
 
-func (Cache) Name() string {
-	return "cache"
+// discard removes everything under dir, relative to root, that is neither a persisted path nor a parent of one.
+func (c Cache) discard(root string, dir string) error {
+	cs, err := ioutil.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return fmt.Errorf("unable to list children of %s\n%w", filepath.Join(root, dir), err)
+	}
+
+	for _, child := range cs {
+		rel := filepath.Join(dir, child.Name())
+
+		keep, parent := false, false
+		for _, p := range c.PersistedPaths {
+			keep = keep || p == rel
+			parent = parent || strings.HasPrefix(p, rel+string(filepath.Separator))
+		}
+
+		if keep {
+			continue
+		}
+
+		if parent && child.IsDir() {
+			if err := c.discard(root, rel); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c.Logger.Bodyf("Discarding %s from cache", rel)
+		if err := os.RemoveAll(filepath.Join(root, rel)); err != nil {
+			return fmt.Errorf("unable to remove %s\n%w", filepath.Join(root, rel), err)
+		}
+	}
+
+	return nil
+}
+
+// resetAccessTimes sets the access time of every file under root to just before its modification time, so that any
+// read of the file during the build moves its access time past its modification time, even with relatime mounts.
+func resetAccessTimes(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == root {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if err := os.Chtimes(path, info.ModTime().Add(-time.Second), info.ModTime()); err != nil {
+			return fmt.Errorf("unable to change times of %s\n%w", path, err)
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,327 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// CacheUsage records, across builds, the last build in which each entry of the caches was used.
+type CacheUsage struct {
+
+	// Builds is the number of builds in which the caches were used.
+	Builds int `json:"builds"`
+
+	// Entries is the last build in which each entry was used, keyed by cache name and path within the cache.
+	Entries map[string]int `json:"entries"`
+}
+
+// CachePruner prunes the entries of caches that have not been used for a number of builds, and then the least recently
+// used entries until the caches fit within a size limit.  An entry is a directory, without subdirectories, under one of
+// a cache's PruneRoots.  It must be contributed after the application so that the access times of the entries reflect
+// the build.
+type CachePruner struct {
+	Caches          []Cache
+	LayersPath      string
+	Logger          bard.Logger
+	MaxSize         int64
+	MaxUnusedBuilds int
+}
+
+// NewCachePruner creates a new CachePruner configured by $BP_CACHE_MAX_UNUSED_BUILDS, defaulting to 5, and
+// $BP_CACHE_MAX_SIZE, defaulting to no limit.  0 disables either limit.
+func NewCachePruner(layersPath string, caches []Cache) (CachePruner, error) {
+	p := CachePruner{Caches: caches, LayersPath: layersPath, MaxUnusedBuilds: 5}
+
+	if s, ok := os.LookupEnv("BP_CACHE_MAX_UNUSED_BUILDS"); ok && s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return CachePruner{}, fmt.Errorf("$BP_CACHE_MAX_UNUSED_BUILDS value %s must be a non-negative integer", s)
+		}
+		p.MaxUnusedBuilds = n
+	}
+
+	if s, ok := os.LookupEnv("BP_CACHE_MAX_SIZE"); ok && s != "" {
+		n, err := ParseSize(s)
+		if err != nil {
+			return CachePruner{}, fmt.Errorf("unable to parse $BP_CACHE_MAX_SIZE value %s\n%w", s, err)
+		}
+		p.MaxSize = n
+	}
+
+	return p, nil
+}
+
+type cacheEntry struct {
+	key  string
+	path string
+	root string
+	size int64
+	used bool
+}
+
+func (c CachePruner) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create layer directory %s\n%w", layer.Path, err)
+	}
+
+	file := filepath.Join(layer.Path, "usage.json")
+	usage := CacheUsage{Entries: map[string]int{}}
+	if b, err := ioutil.ReadFile(file); err == nil {
+		if err := json.Unmarshal(b, &usage); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to decode %s\n%w", file, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	tracked, err := accessTimesTracked(layer.Path)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to determine whether access times are tracked\n%w", err)
+	}
+
+	var entries []cacheEntry
+	for _, cache := range c.Caches {
+		for _, r := range cache.PruneRoots {
+			e, err := scanCacheEntries(filepath.Join(c.LayersPath, cache.Name()), r, tracked)
+			if err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to scan cache %s\n%w", cache.Name(), err)
+			}
+			entries = append(entries, e...)
+		}
+	}
+
+	// A build that used no entry at all was either skipped, because the application layer was reused, or ran without
+	// access times, so it does not count against the entries.
+	counted := false
+	for _, e := range entries {
+		if e.used {
+			counted = true
+			break
+		}
+	}
+	if counted {
+		usage.Builds++
+	} else if !tracked {
+		c.Logger.Body("Access times are not tracked, not pruning unused cache entries")
+	}
+
+	last := map[string]int{}
+	for _, e := range entries {
+		n, ok := usage.Entries[e.key]
+		if !ok || (counted && e.used) {
+			n = usage.Builds
+		}
+		last[e.key] = n
+	}
+
+	// Least recently used first, and larger first among equally recent entries.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if last[entries[i].key] != last[entries[j].key] {
+			return last[entries[i].key] < last[entries[j].key]
+		}
+		return entries[i].size > entries[j].size
+	})
+
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+
+	var pruned int
+	var reclaimed int64
+	usage.Entries = map[string]int{}
+	for _, e := range entries {
+		unused := c.MaxUnusedBuilds > 0 && counted && usage.Builds-last[e.key] >= c.MaxUnusedBuilds
+		oversize := c.MaxSize > 0 && total > c.MaxSize
+
+		if !unused && !oversize {
+			usage.Entries[e.key] = last[e.key]
+			continue
+		}
+
+		if err := removeCacheEntry(e.path, e.root); err != nil {
+			return libcnb.Layer{}, err
+		}
+		total -= e.size
+		reclaimed += e.size
+		pruned++
+	}
+
+	if pruned > 0 {
+		c.Logger.Bodyf("Pruned %d cache entries, reclaimed %s, %s remaining", pruned, FormatSize(reclaimed), FormatSize(total))
+	} else {
+		c.Logger.Bodyf("No cache entries to prune, %s in use", FormatSize(total))
+	}
+
+	b, err := json.Marshal(usage)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode cache usage\n%w", err)
+	}
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	layer.Cache = true
+	return layer, nil
+}
+
+func (CachePruner) Name() string {
+	return "cache-usage"
+}
+
+// accessTimesTracked returns whether reading a file in path moves its access time past its modification time, which is
+// not the case on noatime mounts.
+func accessTimesTracked(path string) (bool, error) {
+	file := filepath.Join(path, ".probe")
+	defer os.Remove(file)
+
+	if err := ioutil.WriteFile(file, []byte("probe"), 0644); err != nil {
+		return false, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	if err := resetAccessTimes(file); err != nil {
+		return false, err
+	}
+
+	if _, err := ioutil.ReadFile(file); err != nil {
+		return false, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, fmt.Errorf("unable to stat %s\n%w", file, err)
+	}
+
+	a, ok := accessTime(info)
+	return ok && !a.Before(info.ModTime()), nil
+}
+
+// scanCacheEntries returns the entries under root, a directory relative to cache.  An entry is used if any of its files
+// was read since its access times were reset.
+func scanCacheEntries(cache string, root string, tracked bool) ([]cacheEntry, error) {
+	dirs := map[string]*cacheEntry{}
+	parents := map[string]bool{}
+
+	base := filepath.Join(cache, root)
+	if err := filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == base {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			parents[filepath.Dir(path)] = true
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		e, ok := dirs[dir]
+		if !ok {
+			rel, err := filepath.Rel(filepath.Dir(cache), dir)
+			if err != nil {
+				return fmt.Errorf("unable to determine relative path of %s\n%w", dir, err)
+			}
+			e = &cacheEntry{key: filepath.ToSlash(rel), path: dir, root: base}
+			dirs[dir] = e
+		}
+
+		e.size += info.Size()
+		if a, ok := accessTime(info); tracked && ok && !a.Before(info.ModTime()) {
+			e.used = true
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error walking path %s\n%w", base, err)
+	}
+
+	var entries []cacheEntry
+	for dir, e := range dirs {
+		if dir != base && !parents[dir] {
+			entries = append(entries, *e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	return entries, nil
+}
+
+// removeCacheEntry removes an entry and then any of its parents, up to root, that are left empty.
+func removeCacheEntry(path string, root string) error {
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("unable to remove %s\n%w", path, err)
+	}
+
+	for dir := filepath.Dir(path); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if cs, err := ioutil.ReadDir(dir); err != nil || len(cs) > 0 {
+			break
+		}
+
+		if err := os.Remove(dir); err != nil {
+			return fmt.Errorf("unable to remove %s\n%w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseSize parses a size in bytes, optionally suffixed with K, M, G, or T for binary multiples, e.g. 2G.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+
+	multiplier := int64(1)
+	if i := strings.IndexAny(s, "KMGT"); i >= 0 && i == len(s)-1 {
+		multiplier = int64(1) << (10 * (strings.Index("KMGT", s[i:]) + 1))
+		s = s[:i]
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse size %s\n%w", s, err)
+	}
+
+	return n * multiplier, nil
+}
+
+// FormatSize formats a size in bytes using binary multiples, e.g. 1.5 GiB.
+func FormatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value, unit := float64(size), 0
+	for value >= 1024 && unit < 4 {
+		value /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[unit-1])
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+)
+
+func testCachePruner(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		b      *bytes.Buffer
+		cache  system.Cache
+		ctx    libcnb.BuildContext
+		pruner system.CachePruner
+	)
+
+	it.Before(func() {
+		var err error
+
+		ctx.Layers.Path, err = ioutil.TempDir("", "cache-pruner-layers")
+		Expect(err).NotTo(HaveOccurred())
+
+		home, err := ioutil.TempDir(ctx.Layers.Path, "home")
+		Expect(err).NotTo(HaveOccurred())
+
+		cache = system.NewMavenCache(filepath.Join(home, ".m2"))
+
+		for _, f := range []string{
+			filepath.Join("repository", "test", "used", "1.0", "used-1.0.jar"),
+			filepath.Join("repository", "test", "unused", "1.0", "unused-1.0.jar"),
+		} {
+			file := filepath.Join(ctx.Layers.Path, cache.Name(), f)
+			Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(file, []byte(filepath.Base(f)), 0644)).To(Succeed())
+		}
+
+		b = bytes.NewBuffer(nil)
+		pruner, err = system.NewCachePruner(ctx.Layers.Path, []system.Cache{cache})
+		Expect(err).NotTo(HaveOccurred())
+		pruner.Logger = bard.NewLogger(b)
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+	})
+
+	build := func() {
+		layer, err := ctx.Layers.Layer(cache.Name())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = cache.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = ioutil.ReadFile(filepath.Join(cache.Path, "repository", "test", "used", "1.0", "used-1.0.jar"))
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = ctx.Layers.Layer(pruner.Name())
+		Expect(err).NotTo(HaveOccurred())
+		layer, err = pruner.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(layer.Cache).To(BeTrue())
+	}
+
+	it("defaults limits", func() {
+		Expect(pruner.MaxUnusedBuilds).To(Equal(5))
+		Expect(pruner.MaxSize).To(BeZero())
+	})
+
+	it("prunes entries unused for a number of builds", func() {
+		pruner.MaxUnusedBuilds = 2
+
+		build()
+		build()
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "unused", "1.0")).To(BeADirectory())
+
+		build()
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "used", "1.0", "used-1.0.jar")).To(BeARegularFile())
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "unused")).NotTo(BeAnExistingFile())
+		Expect(b.String()).To(ContainSubstring("Pruned 1 cache entries, reclaimed 14 B, 12 B remaining"))
+	})
+
+	it("prunes least recently used entries beyond size limit", func() {
+		pruner.MaxUnusedBuilds = 0
+
+		build()
+		pruner.MaxSize = 12
+		build()
+
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "used", "1.0", "used-1.0.jar")).To(BeARegularFile())
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "unused")).NotTo(BeAnExistingFile())
+	})
+
+	it("does not count builds that use no entries", func() {
+		pruner.MaxUnusedBuilds = 1
+
+		build()
+		Expect(os.RemoveAll(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "used"))).To(Succeed())
+
+		layer, err := ctx.Layers.Layer(pruner.Name())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = pruner.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(ctx.Layers.Path, "cache", "repository", "test", "unused", "1.0")).To(BeADirectory())
+	})
+
+	it("parses and formats sizes", func() {
+		Expect(system.ParseSize("1024")).To(Equal(int64(1024)))
+		Expect(system.ParseSize("2K")).To(Equal(int64(2048)))
+		_, err := system.ParseSize("1.5G")
+		Expect(err).To(HaveOccurred())
+		Expect(system.ParseSize("3GB")).To(Equal(int64(3 << 30)))
+
+		Expect(system.FormatSize(512)).To(Equal("512 B"))
+		Expect(system.FormatSize(1536)).To(Equal("1.5 KiB"))
+		Expect(system.FormatSize(2 << 30)).To(Equal("2.0 GiB"))
+	})
+}
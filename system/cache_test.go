@@ -68,4 +68,64 @@ func testCache(t *testing.T, context spec.G, it spec.S) {
 
 		Expect(os.Readlink(file)).To(Equal(layer.Path))
 	})
+
+	it("persists selected paths", func() {
+		file := filepath.Join(path, ".gradle")
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(layer.Path, "caches", "transforms-2"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(layer.Path, "caches", "modules-2", "files-2.1"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(layer.Path, "daemon"), 0755)).To(Succeed())
+
+		cache := system.NewGradleCache(file)
+		Expect(cache.PersistedPaths).To(Equal([]string{
+			filepath.Join("caches", "modules-2"),
+			filepath.Join("wrapper", "dists"),
+		}))
+
+		layer, err = cache.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Cache).To(BeTrue())
+		Expect(filepath.Join(layer.Path, "caches", "modules-2", "files-2.1")).To(BeADirectory())
+		Expect(filepath.Join(layer.Path, "caches", "transforms-2")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(layer.Path, "daemon")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(layer.Path, "wrapper", "dists")).To(BeADirectory())
+
+		fi, err := os.Lstat(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.IsDir()).To(BeTrue())
+
+		Expect(os.Readlink(filepath.Join(file, "caches", "modules-2"))).To(Equal(filepath.Join(layer.Path, "caches", "modules-2")))
+		Expect(os.Readlink(filepath.Join(file, "wrapper", "dists"))).To(Equal(filepath.Join(layer.Path, "wrapper", "dists")))
+	})
+
+	it("symlinks links to paths within the layer", func() {
+		file := filepath.Join(path, "test")
+		link := filepath.Join(path, "test-link")
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = system.Cache{Links: map[string]string{link: "test-directory"}, Path: file}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(layer.Path, "test-directory")).To(BeADirectory())
+		Expect(os.Readlink(file)).To(Equal(layer.Path))
+		Expect(os.Readlink(link)).To(Equal(filepath.Join(layer.Path, "test-directory")))
+	})
+
+	it("prunes well-known caches", func() {
+		Expect(system.NewMavenCache(filepath.Join(path, "test-maven-home")).PruneRoots).To(Equal([]string{"repository"}))
+		Expect(system.NewGradleCache(filepath.Join(path, "test-gradle-home")).PruneRoots).
+			To(Equal([]string{filepath.Join("caches", "modules-2", "files-2.1")}))
+		Expect(system.NewCache(filepath.Join(path, ".m2")).PruneRoots).To(BeEmpty())
+		Expect(system.NewCache(filepath.Join(path, ".gradle")).PersistedPaths).To(BeEmpty())
+	})
+
+	it("qualifies name", func() {
+		Expect(system.Cache{}.Name()).To(Equal("cache"))
+		Expect(system.Cache{Qualifier: "test-qualifier"}.Name()).To(Equal("cache-test-qualifier"))
+	})
 }
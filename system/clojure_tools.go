@@ -0,0 +1,177 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/crush"
+)
+
+type ClojureToolsDistribution struct {
+	LayerContributor libpak.DependencyLayerContributor
+	Logger           bard.Logger
+}
+
+// Contribute lays the Clojure CLI out the same way its linux-install script does: the jars under lib/clojure/libexec
+// and the clojure and clj launchers, with their install locations substituted, under bin.
+func (c ClojureToolsDistribution) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	c.LayerContributor.Logger = c.Logger
+
+	return c.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		lib := filepath.Join(layer.Path, "lib", "clojure")
+		bin := filepath.Join(layer.Path, "bin")
+
+		c.Logger.Bodyf("Expanding to %s", layer.Path)
+		if err := crush.ExtractTarGz(artifact, lib, 1); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to expand Clojure CLI\n%w", err)
+		}
+
+		jars, err := filepath.Glob(filepath.Join(lib, "*.jar"))
+		if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to find jars in %s\n%w", lib, err)
+		}
+
+		libexec := filepath.Join(lib, "libexec")
+		if err := os.MkdirAll(libexec, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", libexec, err)
+		}
+
+		for _, j := range jars {
+			file := filepath.Join(libexec, filepath.Base(j))
+			if err := os.Rename(j, file); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to move %s to %s\n%w", j, file, err)
+			}
+		}
+
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", bin, err)
+		}
+
+		launchers := map[string]struct{ placeholder, value string }{
+			"clojure": {"PREFIX", lib},
+			"clj":     {"BINDIR", bin},
+		}
+		for name, l := range launchers {
+			source := filepath.Join(lib, name)
+			b, err := ioutil.ReadFile(source)
+			if err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to read %s\n%w", source, err)
+			}
+
+			file := filepath.Join(bin, name)
+			b = bytes.ReplaceAll(b, []byte(l.placeholder), []byte(l.value))
+			if err := ioutil.WriteFile(file, b, 0755); err != nil {
+				return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+			}
+		}
+
+		layer.Build = true
+		layer.Cache = true
+		return layer, nil
+	})
+}
+
+func (ClojureToolsDistribution) Name() string {
+	return "clojure-tools"
+}
+
+type ClojureTools struct {
+	Logger bard.Logger
+}
+
+func (ClojureTools) Caches(string) ([]Cache, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return []Cache{
+		NewMavenCache(filepath.Join(u.HomeDir, ".m2")),
+		NewCache(filepath.Join(u.HomeDir, ".gitlibs")),
+	}, nil
+}
+
+func (ClojureTools) DefaultArguments() []string {
+	return []string{"-T:build", "uber"}
+}
+
+func (ClojureTools) DefaultTarget() string {
+	return filepath.Join("target", "*.jar")
+}
+
+func (ClojureTools) Detect(context libcnb.DetectContext, result *libcnb.DetectResult) error {
+	file := filepath.Join(context.Application.Path, "deps.edn")
+	_, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to determine if %s exists\n%w", file, err)
+	}
+
+	result.Pass = true
+	result.Plans = append(result.Plans, libcnb.BuildPlan{
+		Provides: []libcnb.BuildPlanProvide{
+			{Name: "clojure-tools"},
+			{Name: "jvm-application"},
+		},
+		Requires: []libcnb.BuildPlanRequire{
+			{Name: "clojure-tools"},
+			{Name: "jdk"},
+		},
+	})
+
+	return nil
+}
+
+func (ClojureTools) Distribution(layersPath string) string {
+	return filepath.Join(layersPath, "clojure-tools", "bin", "clojure")
+}
+
+func (c ClojureTools) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	dep, err := resolver.Resolve("clojure-tools", "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	return ClojureToolsDistribution{
+		LayerContributor: libpak.NewDependencyLayerContributor(dep, cache, plan),
+		Logger:           c.Logger,
+	}, nil
+}
+
+func (ClojureTools) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
+	_, ok, err := resolver.Resolve("clojure-tools")
+	if err != nil {
+		return false, fmt.Errorf("unable to resolve clojure-tools plan entry\n%w", err)
+	}
+
+	return ok, nil
+}
+
+// Wrappers returns no names as the Clojure CLI has no conventional wrapper script.
+func (ClojureTools) Wrappers() []string {
+	return nil
+}
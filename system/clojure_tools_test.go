@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/sclevine/spec"
+)
+
+func testClojureTools(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		clojureTools system.ClojureTools
+	)
+
+	context("Build", func() {
+		var (
+			ctx libcnb.BuildContext
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Application.Path, err = ioutil.TempDir("", "clojure-tools-application")
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx.Layers.Path, err = ioutil.TempDir("", "clojure-tools-layers")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+			Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+		})
+
+		it("contributes Clojure CLI distribution", func() {
+			dr := libpak.DependencyResolver{
+				Dependencies: []libpak.BuildpackDependency{
+					{
+						ID:      "clojure-tools",
+						Version: "1.1.1",
+						URI:     "https://localhost/stub-clojure-tools.tar.gz",
+						SHA256:  "efada9a5696bcb80faf9a927e1cb74affe952e7401144b10e7a6476f5e8c0a8b",
+						Stacks:  []string{"test-stack-id"},
+					},
+				},
+				StackID: "test-stack-id",
+			}
+
+			dc := libpak.DependencyCache{CachePath: "testdata"}
+
+			d, err := clojureTools.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = d.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Build).To(BeTrue())
+			Expect(layer.Cache).To(BeTrue())
+			Expect(filepath.Join(layer.Path, "lib", "clojure", "libexec", "exec.jar")).To(BeARegularFile())
+			Expect(filepath.Join(layer.Path, "lib", "clojure", "libexec", "clojure-tools-1.1.1.jar")).To(BeARegularFile())
+			Expect(ioutil.ReadFile(filepath.Join(layer.Path, "bin", "clojure"))).To(ContainSubstring(
+				fmt.Sprintf("install_dir=%s", filepath.Join(layer.Path, "lib", "clojure"))))
+			Expect(ioutil.ReadFile(filepath.Join(layer.Path, "bin", "clj"))).To(ContainSubstring(
+				fmt.Sprintf("bin_dir=%s", filepath.Join(layer.Path, "bin"))))
+		})
+
+		it("resolves a default dependency supporting the default arguments", func() {
+			var b libcnb.Buildpack
+			_, err := toml.DecodeFile(filepath.Join("..", "buildpack.toml"), &b)
+			Expect(err).NotTo(HaveOccurred())
+
+			md, err := libpak.NewBuildpackMetadata(b.Metadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			dr := libpak.DependencyResolver{Dependencies: md.Dependencies, StackID: "io.buildpacks.stacks.bionic"}
+			dep, err := dr.Resolve("clojure-tools", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			// -T and tools.build require Clojure CLI 1.10.3.933 or later.
+			Expect(clojureTools.DefaultArguments()).To(ContainElement("-T:build"))
+			Expect(dep.URI).To(MatchRegexp(`/clojure-tools-1\.10\.3\.(93[3-9]|9[4-9][0-9]|[1-9][0-9]{3,})\.tar\.gz$`))
+		})
+
+		it("it participates", func() {
+			pr := libpak.PlanEntryResolver{Plan: libcnb.BuildpackPlan{
+				Entries: []libcnb.BuildpackPlanEntry{
+					{Name: "clojure-tools"},
+				},
+			}}
+
+			Expect(clojureTools.Participate(pr)).To(BeTrue())
+		})
+	})
+
+	context("Detect", func() {
+		var (
+			ctx    libcnb.DetectContext
+			result libcnb.DetectResult
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Application.Path, err = ioutil.TempDir("", "clojure-tools-application")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+		})
+
+		it("does not modify if it does not detect", func() {
+			Expect(clojureTools.Detect(ctx, &result)).To(Succeed())
+
+			Expect(result.Pass).To(BeFalse())
+			Expect(result.Plans).To(HaveLen(0))
+		})
+
+		it("modifies result if deps.edn exists", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "deps.edn"), []byte(""), 0644)).To(Succeed())
+
+			Expect(clojureTools.Detect(ctx, &result)).To(Succeed())
+
+			Expect(result.Pass).To(BeTrue())
+			Expect(result.Plans).To(HaveLen(1))
+			Expect(result.Plans[0]).To(Equal(libcnb.BuildPlan{
+				Provides: []libcnb.BuildPlanProvide{
+					{Name: "clojure-tools"},
+					{Name: "jvm-application"},
+				},
+				Requires: []libcnb.BuildPlanRequire{
+					{Name: "clojure-tools"},
+					{Name: "jdk"},
+				},
+			}))
+		})
+	})
+}
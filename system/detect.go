@@ -22,6 +22,8 @@ import (
 	"github.com/buildpacks/libcnb"
 )
 
+// Detect detects each of the Systems in turn.  The plan of the first system that passes is the primary plan, so
+// Systems are ordered by precedence.
 type Detect struct {
 	Systems []System
 }
@@ -18,16 +18,26 @@ package system
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/paketo-buildpacks/libpak/crush"
 )
 
+var (
+	gradleInclude = regexp.MustCompile(`\binclude\s*\(?((?:\s*["'][^"']+["']\s*,?)+)`)
+	gradleString  = regexp.MustCompile(`["']([^"']+)["']`)
+	gradleVersion = regexp.MustCompile(`gradle-([^/]+)-(?:bin|all)\.zip$`)
+)
+
 type GradleDistribution struct {
 	LayerContributor libpak.DependencyLayerContributor
 	Logger           bard.Logger
@@ -52,6 +62,119 @@ func (GradleDistribution) Name() string {
 	return "gradle"
 }
 
+// GradleBinding links the gradle.properties and init.gradle of a binding of kind gradle into the Gradle user home.  The
+// files are linked rather than copied so that credentials are never persisted in a cached layer.
+type GradleBinding struct {
+	Binding    libcnb.Binding
+	GradleHome string
+	Logger     bard.Logger
+	Platform   libcnb.Platform
+}
+
+func (g GradleBinding) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(g.GradleHome, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", g.GradleHome, err)
+	}
+
+	for _, name := range []string{"gradle.properties", "init.gradle"} {
+		source, ok := SecretPath(g.Platform, g.Binding, name)
+		if !ok {
+			continue
+		}
+
+		file := filepath.Join(g.GradleHome, name)
+		if err := os.RemoveAll(file); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", file, err)
+		}
+
+		g.Logger.Bodyf("Linking %s from binding %s", name, g.Binding.Name)
+		if err := os.Symlink(source, file); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to link %s to %s\n%w", source, file, err)
+		}
+	}
+
+	return layer, nil
+}
+
+func (GradleBinding) Name() string {
+	return "gradle-binding"
+}
+
+// GradleProjects returns the directories, relative to applicationPath, of the projects included by the
+// settings.gradle or settings.gradle.kts in applicationPath.  Commented out includes are ignored.  Projects are assumed
+// to be in their default directories, so project :a:b is in a/b.
+func GradleProjects(applicationPath string) ([]string, error) {
+	var projects []string
+
+	for _, f := range []string{"settings.gradle", "settings.gradle.kts"} {
+		file := filepath.Join(applicationPath, f)
+
+		b, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		for _, include := range gradleInclude.FindAllStringSubmatch(stripGradleComments(string(b)), -1) {
+			for _, s := range gradleString.FindAllStringSubmatch(include[1], -1) {
+				// Including a:b also includes its parent a
+				segments := strings.Split(strings.TrimPrefix(s[1], ":"), ":")
+				for i := range segments {
+					if p := strings.Join(segments[:i+1], "/"); !containsString(projects, p) {
+						projects = append(projects, p)
+					}
+				}
+			}
+		}
+	}
+
+	return projects, nil
+}
+
+// stripGradleComments removes the // and /* */ comments, outside of string literals, from a Groovy or Kotlin script.
+func stripGradleComments(script string) string {
+	b := &strings.Builder{}
+
+	var quote byte
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(script) {
+				b.WriteByte(c)
+				i++
+				c = script[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(script[i:], "//"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			if i < len(script) {
+				b.WriteByte('\n')
+			}
+			continue
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+			continue
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
 type Gradle struct {
 	Logger bard.Logger
 }
@@ -86,17 +209,85 @@ func (Gradle) Detect(context libcnb.DetectContext, result *libcnb.DetectResult)
 	return nil
 }
 
-func (Gradle) CachePath() (string, error) {
-	u, err := user.Current()
+// AdditionalArguments checks that $BP_BUILT_MODULE, if set, is a project included in the Gradle settings when the
+// default arguments, which select the build task of that project, are used.  A module that is not found is only
+// warned about, as a project's directory may be remapped in the settings.  No additional arguments are returned.
+func (g Gradle) AdditionalArguments(context libcnb.BuildContext) ([]string, error) {
+	module := os.Getenv("BP_BUILT_MODULE")
+	if module == "" {
+		return nil, nil
+	}
+	if _, ok := os.LookupEnv("BP_BUILD_ARGUMENTS"); ok {
+		return nil, nil
+	}
+
+	projects, err := GradleProjects(context.Application.Path)
 	if err != nil {
-		return "", fmt.Errorf("unable to determine user home directory\n%w", err)
+		return nil, fmt.Errorf("unable to determine Gradle projects\n%w", err)
 	}
 
-	return filepath.Join(u.HomeDir, ".gradle"), nil
+	module = filepath.ToSlash(filepath.Clean(module))
+	if !containsString(projects, module) {
+		g.Logger.Bodyf("Warning: module %s is not a project included in the Gradle settings, projects: %s", module, projects)
+	}
+
+	return nil, nil
 }
 
+// AdditionalLayers returns a layer that links the configuration of a binding of kind gradle into the Gradle user home
+// the build uses.  Returns no layers if there is no such binding.
+func (g Gradle) AdditionalLayers(context libcnb.BuildContext) ([]libcnb.LayerContributor, error) {
+	b, ok, err := ResolveBinding(context.Platform, "gradle")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	home, err := gradleUserHome(context.Application.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return []libcnb.LayerContributor{
+		GradleBinding{Binding: b, GradleHome: home, Logger: g.Logger, Platform: context.Platform},
+	}, nil
+}
+
+// Caches returns the Gradle user home the build uses.  The wrapper does not read gradle.properties, so if the home is
+// set there, the wrapper's distributions are linked to the cache's.
+func (Gradle) Caches(applicationPath string) ([]Cache, error) {
+	wrapper, err := gradleWrapperHome(applicationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := gradleUserHome(applicationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewGradleCache(home)
+
+	if home != wrapper {
+		c.Links = map[string]string{filepath.Join(wrapper, "wrapper", "dists"): filepath.Join("wrapper", "dists")}
+	}
+
+	return []Cache{c}, nil
+}
+
+// DefaultArguments runs the build task of the project for $BP_BUILT_MODULE, or of all projects if it is not set.
 func (Gradle) DefaultArguments() []string {
-	return []string{"--no-daemon", "-x", "test", "build"}
+	task := "build"
+	if module := os.Getenv("BP_BUILT_MODULE"); module != "" {
+		task = fmt.Sprintf(":%s:build", strings.ReplaceAll(filepath.ToSlash(filepath.Clean(module)), "/", ":"))
+	}
+
+	if ok, _ := RunTests(); ok {
+		return []string{"--no-daemon", task}
+	}
+
+	return []string{"--no-daemon", "-x", "test", task}
 }
 
 func (Gradle) DefaultTarget() string {
@@ -107,9 +298,16 @@ func (Gradle) Distribution(layersPath string) string {
 	return filepath.Join(layersPath, "gradle", "bin", "gradle")
 }
 
-func (g Gradle) DistributionLayer(resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
-	dep, err := resolver.Resolve("gradle", "")
+func (g Gradle) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	v, source, err := DistributionVersion("BP_GRADLE_VERSION", filepath.Join(applicationPath, "gradle", "wrapper", "gradle-wrapper.properties"), gradleVersion)
 	if err != nil {
+		return nil, fmt.Errorf("unable to determine Gradle version\n%w", err)
+	}
+
+	dep, err := resolver.Resolve("gradle", v)
+	if v != "" && libpak.IsNoValidDependencies(err) {
+		return nil, fmt.Errorf("no Gradle distribution matching version %s from %s is available\n%w", v, source, err)
+	} else if err != nil {
 		return nil, fmt.Errorf("unable to find depdency\n%w", err)
 	}
 
@@ -128,6 +326,100 @@ func (Gradle) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
 	return ok, nil
 }
 
-func (Gradle) Wrapper() string {
-	return "gradlew"
+func (Gradle) TestReports() []string {
+	return []string{
+		filepath.Join("build", "test-results", "*", "TEST-*.xml"),
+		filepath.Join("*", "build", "test-results", "*", "TEST-*.xml"),
+	}
+}
+
+func (Gradle) Wrappers() []string {
+	return []string{"gradlew"}
+}
+
+func (Gradle) WrapperDistribution(applicationPath string) (WrapperDistribution, bool, error) {
+	return NewWrapperDistribution(filepath.Join(applicationPath, "gradle", "wrapper", "gradle-wrapper.properties"), gradleVersion)
+}
+
+// WrapperDistributionLayer returns a layer that seeds the wrapper/dists of the Gradle user home the build uses with the
+// Gradle dependency matching the wrapper's version.  Returns false if the version is unknown, there is no such
+// dependency, or the wrapper's distributionSha256Sum does not match the dependency, in which case the wrapper downloads
+// the distribution itself.
+func (g Gradle) WrapperDistributionLayer(applicationPath string, distribution WrapperDistribution, resolver libpak.DependencyResolver, cache libpak.DependencyCache) (libcnb.LayerContributor, bool, error) {
+	if distribution.Version == "" {
+		return nil, false, nil
+	}
+
+	dep, err := resolver.Resolve("gradle", distribution.Version)
+	if libpak.IsNoValidDependencies(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	if !distribution.Accepts(dep.SHA256) {
+		g.Logger.Bodyf("Not seeding wrapper distribution as its distributionSha256Sum %s does not match %s %s",
+			distribution.DistributionSHA256, dep.Name, dep.Version)
+		return nil, false, nil
+	}
+
+	home, err := gradleUserHome(applicationPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return WrapperDistributionLayer{
+		Dependency:        dep,
+		DependencyCache:   cache,
+		Distribution:      distribution,
+		DistributionsPath: filepath.Join(home, "wrapper", "dists"),
+		LayerName:         "gradle-wrapper",
+		Logger:            g.Logger,
+	}, true, nil
+}
+
+// gradleUserHome returns the Gradle user home the build uses: the gradle.user.home system property set in the project's
+// gradle.properties, which supersedes $GRADLE_USER_HOME, defaulting to ~/.gradle.  Relative paths are relative to the
+// application root.
+func gradleUserHome(applicationPath string) (string, error) {
+	file := filepath.Join(applicationPath, "gradle.properties")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return gradleWrapperHome(applicationPath)
+	} else if err != nil {
+		return "", fmt.Errorf("unable to determine if %s exists\n%w", file, err)
+	}
+
+	// Gradle does not expand references in gradle.properties.
+	p, err := (&properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}).LoadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read properties from %s\n%w", file, err)
+	}
+
+	home := p.GetString("systemProp.gradle.user.home", "")
+	if home == "" {
+		return gradleWrapperHome(applicationPath)
+	}
+	if !filepath.IsAbs(home) {
+		home = filepath.Join(applicationPath, home)
+	}
+
+	return home, nil
+}
+
+// gradleWrapperHome returns the Gradle user home the wrapper uses: $GRADLE_USER_HOME, defaulting to ~/.gradle.
+// Relative paths are relative to the application root.
+func gradleWrapperHome(applicationPath string) (string, error) {
+	if home, ok := os.LookupEnv("GRADLE_USER_HOME"); ok {
+		if !filepath.IsAbs(home) {
+			home = filepath.Join(applicationPath, home)
+		}
+		return home, nil
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return filepath.Join(u.HomeDir, ".gradle"), nil
 }
@@ -17,8 +17,11 @@
 package system_test
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
 
@@ -26,6 +29,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/paketo-buildpacks/build-system/system"
 	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/sclevine/spec"
 )
 
@@ -72,7 +76,7 @@ func testGradle(t *testing.T, context spec.G, it spec.S) {
 
 			dc := libpak.DependencyCache{CachePath: "testdata"}
 
-			d, err := gradle.DistributionLayer(dr, dc, &libcnb.BuildpackPlan{})
+			d, err := gradle.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
 			Expect(err).NotTo(HaveOccurred())
 
 			layer, err := ctx.Layers.Layer("test-layer")
@@ -86,6 +90,73 @@ func testGradle(t *testing.T, context spec.G, it spec.S) {
 			Expect(filepath.Join(layer.Path, "fixture-marker")).To(BeARegularFile())
 		})
 
+		context("distribution version", func() {
+			var (
+				dc libpak.DependencyCache
+				dr libpak.DependencyResolver
+			)
+
+			it.Before(func() {
+				dr = libpak.DependencyResolver{
+					Dependencies: []libpak.BuildpackDependency{
+						{ID: "gradle", Version: "1.1.1", Stacks: []string{"test-stack-id"}},
+						{ID: "gradle", Version: "2.2.2", Stacks: []string{"test-stack-id"}},
+						{ID: "gradle", Version: "3.3.3", Stacks: []string{"test-stack-id"}},
+					},
+					StackID: "test-stack-id",
+				}
+
+				Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "gradle", "wrapper"), 0755)).To(Succeed())
+			})
+
+			it("uses latest version without wrapper properties", func() {
+				d, err := gradle.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(d.(system.GradleDistribution).LayerContributor.Dependency.Version).To(Equal("3.3.3"))
+			})
+
+			it("uses version from wrapper properties", func() {
+				Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, "gradle", "wrapper"), "gradle-wrapper.properties"),
+					[]byte(`distributionUrl=https\://services.gradle.org/distributions/gradle-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+				d, err := gradle.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(d.(system.GradleDistribution).LayerContributor.Dependency.Version).To(Equal("2.2.2"))
+			})
+
+			it("fails if version is not available", func() {
+				dr.Dependencies = dr.Dependencies[:1]
+				Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, "gradle", "wrapper"), "gradle-wrapper.properties"),
+					[]byte(`distributionUrl=https\://services.gradle.org/distributions/gradle-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+				_, err := gradle.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).To(MatchError(HavePrefix(fmt.Sprintf("no Gradle distribution matching version 2.2.2 from %s is available",
+					filepath.Join(filepath.Join(ctx.Application.Path, "gradle", "wrapper"), "gradle-wrapper.properties")))))
+			})
+
+			context("$BP_GRADLE_VERSION", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_GRADLE_VERSION", "1.*")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_GRADLE_VERSION")).To(Succeed())
+				})
+
+				it("uses version from $BP_GRADLE_VERSION", func() {
+					Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, "gradle", "wrapper"), "gradle-wrapper.properties"),
+						[]byte(`distributionUrl=https\://services.gradle.org/distributions/gradle-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+					d, err := gradle.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(d.(system.GradleDistribution).LayerContributor.Dependency.Version).To(Equal("1.1.1"))
+				})
+			})
+		})
+
 		it("it participates", func() {
 			pr := libpak.PlanEntryResolver{Plan: libcnb.BuildpackPlan{
 				Entries: []libcnb.BuildpackPlanEntry{
@@ -97,6 +168,373 @@ func testGradle(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("AdditionalLayers", func() {
+		var (
+			ctx libcnb.BuildContext
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Application.Path, err = ioutil.TempDir("", "gradle")
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx.Platform.Path = "/platform"
+			ctx.Platform.Bindings = libcnb.Bindings{
+				{
+					Name:     "test-binding",
+					Metadata: map[string]string{libcnb.BindingKind: "gradle"},
+					Secret:   map[string]string{"gradle.properties": "test-properties"},
+				},
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("GRADLE_USER_HOME")).To(Succeed())
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+		})
+
+		it("returns no layers without binding", func() {
+			ctx.Platform.Bindings = nil
+
+			Expect(gradle.AdditionalLayers(ctx)).To(BeEmpty())
+		})
+
+		it("returns binding layer", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "/test-gradle-home")).To(Succeed())
+
+			layers, err := gradle.AdditionalLayers(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layers).To(HaveLen(1))
+			Expect(layers[0].Name()).To(Equal("gradle-binding"))
+			Expect(layers[0].(system.GradleBinding).GradleHome).To(Equal("/test-gradle-home"))
+		})
+
+		it("returns binding layer for relative $GRADLE_USER_HOME", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "test-gradle-home")).To(Succeed())
+
+			layers, err := gradle.AdditionalLayers(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layers[0].(system.GradleBinding).GradleHome).To(Equal(filepath.Join(ctx.Application.Path, "test-gradle-home")))
+		})
+
+		it("returns binding layer for Gradle user home from gradle.properties", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "/test-gradle-home")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "gradle.properties"),
+				[]byte("systemProp.gradle.user.home=/test-other-gradle-home\n"), 0644)).To(Succeed())
+
+			layers, err := gradle.AdditionalLayers(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layers[0].(system.GradleBinding).GradleHome).To(Equal("/test-other-gradle-home"))
+		})
+	})
+
+	context("WrapperDistributionLayer", func() {
+		var (
+			dc   libpak.DependencyCache
+			dr   libpak.DependencyResolver
+			path string
+		)
+
+		it.Before(func() {
+			var err error
+
+			path, err = ioutil.TempDir("", "gradle")
+			Expect(err).NotTo(HaveOccurred())
+
+			dr = libpak.DependencyResolver{
+				Dependencies: []libpak.BuildpackDependency{
+					{ID: "gradle", Version: "1.1.1", SHA256: "test-sha256", Stacks: []string{"test-stack-id"}},
+				},
+				StackID: "test-stack-id",
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("GRADLE_USER_HOME")).To(Succeed())
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns false without version", func() {
+			_, ok, err := gradle.WrapperDistributionLayer(path, system.WrapperDistribution{}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns false without matching dependency", func() {
+			_, ok, err := gradle.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "2.2.2"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns false with mismatched checksum", func() {
+			d := system.WrapperDistribution{
+				WrapperProperties: system.WrapperProperties{DistributionSHA256: "test-other-sha256"},
+				Version:           "1.1.1",
+			}
+
+			_, ok, err := gradle.WrapperDistributionLayer(path, d, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns layer with matching checksum", func() {
+			d := system.WrapperDistribution{
+				WrapperProperties: system.WrapperProperties{DistributionSHA256: "TEST-SHA256"},
+				Version:           "1.1.1",
+			}
+
+			_, ok, err := gradle.WrapperDistributionLayer(path, d, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		it("returns layer seeding Gradle user home", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "/test-gradle-home")).To(Succeed())
+
+			l, ok, err := gradle.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			Expect(l.Name()).To(Equal("gradle-wrapper"))
+			Expect(l.(system.WrapperDistributionLayer).Dependency.Version).To(Equal("1.1.1"))
+			Expect(l.(system.WrapperDistributionLayer).DistributionsPath).To(Equal(filepath.Join("/test-gradle-home", "wrapper", "dists")))
+		})
+
+		it("returns layer seeding Gradle user home from gradle.properties", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "/test-gradle-home")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "gradle.properties"),
+				[]byte("systemProp.gradle.user.home=test-other-gradle-home\n"), 0644)).To(Succeed())
+
+			l, ok, err := gradle.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			Expect(l.(system.WrapperDistributionLayer).DistributionsPath).
+				To(Equal(filepath.Join(path, "test-other-gradle-home", "wrapper", "dists")))
+		})
+	})
+
+	context("Caches", func() {
+		var (
+			home string
+			path string
+		)
+
+		it.Before(func() {
+			u, err := user.Current()
+			Expect(err).NotTo(HaveOccurred())
+			home = filepath.Join(u.HomeDir, ".gradle")
+
+			path, err = ioutil.TempDir("", "gradle")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("GRADLE_USER_HOME")).To(Succeed())
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns default Gradle user home", func() {
+			caches, err := gradle.Caches(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caches).To(HaveLen(1))
+			Expect(caches[0].Path).To(Equal(home))
+			Expect(caches[0].PersistedPaths).NotTo(BeEmpty())
+			Expect(caches[0].Links).To(BeEmpty())
+		})
+
+		it("returns $GRADLE_USER_HOME", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "test-gradle-home")).To(Succeed())
+
+			caches, err := gradle.Caches(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caches[0].Path).To(Equal(filepath.Join(path, "test-gradle-home")))
+			Expect(caches[0].PersistedPaths).NotTo(BeEmpty())
+			Expect(caches[0].PruneRoots).NotTo(BeEmpty())
+			Expect(caches[0].Links).To(BeEmpty())
+		})
+
+		it("returns Gradle user home from gradle.properties", func() {
+			Expect(os.Setenv("GRADLE_USER_HOME", "/test-gradle-home")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "gradle.properties"),
+				[]byte("org.gradle.caching=true\nsystemProp.gradle.user.home=/test-other-gradle-home\n"), 0644)).To(Succeed())
+
+			caches, err := gradle.Caches(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caches[0].Path).To(Equal("/test-other-gradle-home"))
+			Expect(caches[0].Links).To(Equal(map[string]string{
+				filepath.Join("/test-gradle-home", "wrapper", "dists"): filepath.Join("wrapper", "dists"),
+			}))
+		})
+	})
+
+	context("GradleBinding", func() {
+		var (
+			binding system.GradleBinding
+			layers  libcnb.Layers
+		)
+
+		it.Before(func() {
+			var err error
+
+			binding.Platform.Path, err = ioutil.TempDir("", "gradle-platform")
+			Expect(err).NotTo(HaveOccurred())
+
+			binding.GradleHome, err = ioutil.TempDir("", "gradle-home")
+			Expect(err).NotTo(HaveOccurred())
+
+			layers.Path, err = ioutil.TempDir("", "gradle-layers")
+			Expect(err).NotTo(HaveOccurred())
+
+			binding.Binding = libcnb.Binding{
+				Name:     "test-binding",
+				Metadata: map[string]string{libcnb.BindingKind: "gradle"},
+				Secret: map[string]string{
+					"gradle.properties": "test-properties",
+					"init.gradle":       "test-init",
+				},
+			}
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(binding.Platform.Path)).To(Succeed())
+			Expect(os.RemoveAll(binding.GradleHome)).To(Succeed())
+			Expect(os.RemoveAll(layers.Path)).To(Succeed())
+		})
+
+		it("links binding files into Gradle home", func() {
+			Expect(ioutil.WriteFile(filepath.Join(binding.GradleHome, "gradle.properties"), []byte("stale"), 0644)).To(Succeed())
+
+			layer, err := layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = binding.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Build).To(BeFalse())
+			Expect(layer.Cache).To(BeFalse())
+			Expect(layer.Launch).To(BeFalse())
+
+			for _, name := range []string{"gradle.properties", "init.gradle"} {
+				Expect(os.Readlink(filepath.Join(binding.GradleHome, name))).
+					To(Equal(filepath.Join(binding.Platform.Path, "bindings", "test-binding", "secret", name)))
+			}
+		})
+	})
+
+	context("DefaultArguments", func() {
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILD_RUN_TESTS")).To(Succeed())
+		})
+
+		it("skips tests", func() {
+			Expect(gradle.DefaultArguments()).To(Equal([]string{"--no-daemon", "-x", "test", "build"}))
+		})
+
+		it("runs tests with $BP_BUILD_RUN_TESTS", func() {
+			Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+
+			Expect(gradle.DefaultArguments()).To(Equal([]string{"--no-daemon", "build"}))
+		})
+
+		it("builds project with $BP_BUILT_MODULE", func() {
+			Expect(os.Setenv("BP_BUILT_MODULE", "test-alpha/test-bravo")).To(Succeed())
+			defer os.Unsetenv("BP_BUILT_MODULE")
+
+			Expect(gradle.DefaultArguments()).To(Equal([]string{"--no-daemon", "-x", "test", ":test-alpha:test-bravo:build"}))
+		})
+	})
+
+	context("projects", func() {
+		var (
+			ctx libcnb.BuildContext
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Application.Path, err = ioutil.TempDir("", "gradle-application")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+			Expect(os.Unsetenv("BP_BUILT_MODULE")).To(Succeed())
+		})
+
+		it("lists projects from settings.gradle", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "settings.gradle"), []byte(`rootProject.name = 'test'
+include 'test-alpha', ':test-bravo'
+include 'test-charlie:test-delta',
+        'test-echo'
+`), 0644)).To(Succeed())
+
+			Expect(system.GradleProjects(ctx.Application.Path)).To(Equal([]string{
+				"test-alpha", "test-bravo", "test-charlie", "test-charlie/test-delta", "test-echo",
+			}))
+		})
+
+		it("lists projects from settings.gradle.kts", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "settings.gradle.kts"), []byte(`rootProject.name = "test"
+include("test-alpha", ":test-bravo")
+include(":test-charlie")
+`), 0644)).To(Succeed())
+
+			Expect(system.GradleProjects(ctx.Application.Path)).To(Equal([]string{"test-alpha", "test-bravo", "test-charlie"}))
+		})
+
+		it("accepts included $BP_BUILT_MODULE", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "settings.gradle"), []byte(`include 'test-alpha'`), 0644)).
+				To(Succeed())
+			Expect(os.Setenv("BP_BUILT_MODULE", "test-alpha")).To(Succeed())
+
+			Expect(gradle.AdditionalArguments(ctx)).To(BeEmpty())
+		})
+
+		it("ignores commented out includes", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "settings.gradle"), []byte(`rootProject.name = 'test'
+include 'test-alpha' // include 'test-bravo'
+// include 'test-charlie'
+/* include 'test-delta'
+include 'test-echo' */
+include 'test-foxtrot//test'
+`), 0644)).To(Succeed())
+
+			Expect(system.GradleProjects(ctx.Application.Path)).To(Equal([]string{"test-alpha", "test-foxtrot//test"}))
+		})
+
+		it("warns about unknown $BP_BUILT_MODULE", func() {
+			Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "settings.gradle"), []byte(`include 'test-alpha'`), 0644)).
+				To(Succeed())
+			Expect(os.Setenv("BP_BUILT_MODULE", "test-bravo")).To(Succeed())
+
+			b := &bytes.Buffer{}
+			gradle.Logger = bard.NewLogger(b)
+
+			Expect(gradle.AdditionalArguments(ctx)).To(BeEmpty())
+			Expect(b.String()).To(ContainSubstring("Warning: module test-bravo is not a project included in the Gradle settings, projects: [test-alpha]"))
+		})
+
+		it("does not check $BP_BUILT_MODULE with $BP_BUILD_ARGUMENTS", func() {
+			Expect(os.Setenv("BP_BUILT_MODULE", "test-bravo")).To(Succeed())
+			Expect(os.Setenv("BP_BUILD_ARGUMENTS", "test-argument")).To(Succeed())
+			defer os.Unsetenv("BP_BUILD_ARGUMENTS")
+
+			b := &bytes.Buffer{}
+			gradle.Logger = bard.NewLogger(b)
+
+			Expect(gradle.AdditionalArguments(ctx)).To(BeEmpty())
+			Expect(b.String()).To(BeEmpty())
+		})
+	})
+
 	context("Detect", func() {
 		var (
 			ctx    libcnb.DetectContext
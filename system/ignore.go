@@ -0,0 +1,252 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+// DefaultIgnorePatterns are the patterns of files that never affect a build: version control metadata, IDE files, and
+// installed Node.js packages.
+var DefaultIgnorePatterns = []string{
+	".git/",
+	".hg/",
+	".svn/",
+	".idea/",
+	".vscode/",
+	"*.iml",
+	".DS_Store",
+	"node_modules/",
+}
+
+// Ignore decides which files under an application root are irrelevant to the build, using .gitignore style patterns.
+// As with .gitignore, the last pattern that matches a path decides whether it is ignored, a pattern prefixed with !
+// includes a path again, a pattern suffixed with / only matches directories, and a pattern containing a / is anchored
+// to the application root.
+type Ignore struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	directory  bool
+	expression *regexp.Regexp
+	negate     bool
+}
+
+// NewIgnore creates a new Ignore from patterns.  Blank patterns and patterns starting with # are skipped.
+func NewIgnore(patterns []string) (Ignore, error) {
+	var i Ignore
+
+	for _, p := range patterns {
+		p = strings.TrimRight(p, " \t\r")
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		var ip ignorePattern
+		if strings.HasPrefix(p, "!") {
+			ip.negate, p = true, p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			ip.directory, p = true, strings.TrimRight(p, "/")
+		}
+
+		anchored := strings.Contains(p, "/")
+		p = strings.TrimPrefix(p, "/")
+		if p == "" {
+			continue
+		}
+
+		e := globExpression(p)
+		if anchored {
+			e = "^" + e + "$"
+		} else {
+			e = "(^|/)" + e + "$"
+		}
+
+		var err error
+		if ip.expression, err = regexp.Compile(e); err != nil {
+			return Ignore{}, fmt.Errorf("unable to parse ignore pattern %s\n%w", p, err)
+		}
+
+		i.patterns = append(i.patterns, ip)
+	}
+
+	return i, nil
+}
+
+// LoadIgnore creates a new Ignore for an application from the DefaultIgnorePatterns, the application's .gitignore if
+// $BP_BUILD_USE_GITIGNORE is true, and the colon separated patterns in $BP_BUILD_EXCLUDE, in that order.  The exclude
+// list of project.toml is not read, as it is applied, and removed, by the platform before the build.
+func LoadIgnore(applicationPath string) (Ignore, error) {
+	patterns := append([]string{}, DefaultIgnorePatterns...)
+
+	if v, ok := os.LookupEnv("BP_BUILD_USE_GITIGNORE"); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Ignore{}, fmt.Errorf("unable to parse $BP_BUILD_USE_GITIGNORE value %s\n%w", v, err)
+		}
+
+		if b {
+			file := filepath.Join(applicationPath, ".gitignore")
+			in, err := os.Open(file)
+			if err == nil {
+				defer in.Close()
+
+				s := bufio.NewScanner(in)
+				for s.Scan() {
+					patterns = append(patterns, s.Text())
+				}
+				if err := s.Err(); err != nil {
+					return Ignore{}, fmt.Errorf("unable to read %s\n%w", file, err)
+				}
+			} else if !os.IsNotExist(err) {
+				return Ignore{}, fmt.Errorf("unable to open %s\n%w", file, err)
+			}
+		}
+	}
+
+	patterns = append(patterns, strings.Split(os.Getenv("BP_BUILD_EXCLUDE"), ":")...)
+
+	return NewIgnore(patterns)
+}
+
+// Ignored returns whether the path, relative to the application root and using / as a separator, is ignored.
+func (i Ignore) Ignored(path string, directory bool) bool {
+	ignored := false
+
+	for _, p := range i.patterns {
+		if p.directory && !directory {
+			continue
+		}
+
+		if p.expression.MatchString(path) {
+			ignored = !p.negate
+		}
+	}
+
+	return ignored
+}
+
+// NewSourceListing generates a listing of the entries under root that are not ignored, with their paths relative to
+// root.  Ignored directories are not descended into.  Unlike sherpa.NewFileListing, modification times are not
+// recorded, as they change whenever the source is checked out, even when its contents do not.
+func NewSourceListing(root string, ignore Ignore) ([]sherpa.FileEntry, error) {
+	var entries []sherpa.FileEntry
+
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("unable to determine relative path of %s\n%w", path, err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		if ignore.Ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		e := sherpa.FileEntry{Path: rel, Mode: info.Mode().String()}
+		if info.Mode().IsRegular() {
+			if e.SHA256, err = hashFile(path); err != nil {
+				return err
+			}
+		}
+
+		entries = append(entries, e)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error walking path %s\n%w", root, err)
+	}
+
+	return entries, nil
+}
+
+// globExpression translates a .gitignore style glob into a regular expression.  ** matches any number of directories,
+// * and ? match within a single path element, and [...] matches a character class.
+func globExpression(glob string) string {
+	b := &strings.Builder{}
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if j := strings.IndexByte(glob[i:], ']'); j > 0 {
+				class := glob[i+1 : i+j]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += j
+			} else {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
+
+func hashFile(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file %s\n%w", path, err)
+	}
+	defer in.Close()
+
+	s := sha256.New()
+	if _, err := io.Copy(s, in); err != nil {
+		return "", fmt.Errorf("unable to hash file %s\n%w", path, err)
+	}
+
+	return hex.EncodeToString(s.Sum(nil)), nil
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/sclevine/spec"
+)
+
+func testIgnore(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "ignore")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("Ignored", func() {
+		it("matches names at any level", func() {
+			i, err := system.NewIgnore([]string{"*.log", "# comment", ""})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("test.log", false)).To(BeTrue())
+			Expect(i.Ignored("logs/test.log", false)).To(BeTrue())
+			Expect(i.Ignored("test.txt", false)).To(BeFalse())
+		})
+
+		it("anchors patterns containing a separator", func() {
+			i, err := system.NewIgnore([]string{"/target", "docs/*.md"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("target", true)).To(BeTrue())
+			Expect(i.Ignored("module/target", true)).To(BeFalse())
+			Expect(i.Ignored("docs/README.md", false)).To(BeTrue())
+			Expect(i.Ignored("docs/api/README.md", false)).To(BeFalse())
+		})
+
+		it("matches directories only", func() {
+			i, err := system.NewIgnore([]string{"build/"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("build", true)).To(BeTrue())
+			Expect(i.Ignored("module/build", true)).To(BeTrue())
+			Expect(i.Ignored("build", false)).To(BeFalse())
+		})
+
+		it("matches any number of directories", func() {
+			i, err := system.NewIgnore([]string{"**/generated/**/*.java"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("generated/Test.java", false)).To(BeTrue())
+			Expect(i.Ignored("src/generated/a/b/Test.java", false)).To(BeTrue())
+			Expect(i.Ignored("src/main/Test.java", false)).To(BeFalse())
+		})
+
+		it("includes negated patterns again", func() {
+			i, err := system.NewIgnore([]string{"*.properties", "!application.properties"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("local.properties", false)).To(BeTrue())
+			Expect(i.Ignored("application.properties", false)).To(BeFalse())
+		})
+	})
+
+	context("LoadIgnore", func() {
+		it("uses default patterns", func() {
+			i, err := system.LoadIgnore(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored(".git", true)).To(BeTrue())
+			Expect(i.Ignored("node_modules", true)).To(BeTrue())
+			Expect(i.Ignored("test.iml", false)).To(BeTrue())
+			Expect(i.Ignored("pom.xml", false)).To(BeFalse())
+		})
+
+		it("does not read .gitignore by default", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, ".gitignore"), []byte("*.log\n"), 0644)).To(Succeed())
+
+			i, err := system.LoadIgnore(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(i.Ignored("test.log", false)).To(BeFalse())
+		})
+
+		context("$BP_BUILD_USE_GITIGNORE", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_BUILD_USE_GITIGNORE", "true")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_BUILD_USE_GITIGNORE")).To(Succeed())
+			})
+
+			it("reads .gitignore", func() {
+				Expect(ioutil.WriteFile(filepath.Join(path, ".gitignore"), []byte("*.log\n!node_modules/\n"), 0644)).To(Succeed())
+
+				i, err := system.LoadIgnore(path)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(i.Ignored("test.log", false)).To(BeTrue())
+				Expect(i.Ignored("node_modules", true)).To(BeFalse())
+			})
+		})
+
+		context("$BP_BUILD_EXCLUDE", func() {
+			it.Before(func() {
+				Expect(os.Setenv("BP_BUILD_EXCLUDE", "docs/:*.md")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_BUILD_EXCLUDE")).To(Succeed())
+			})
+
+			it("reads exclude list", func() {
+				i, err := system.LoadIgnore(path)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(i.Ignored("docs", true)).To(BeTrue())
+				Expect(i.Ignored("README.md", false)).To(BeTrue())
+				Expect(i.Ignored("src", true)).To(BeFalse())
+			})
+		})
+	})
+
+	it("lists sources that are not ignored", func() {
+		Expect(os.MkdirAll(filepath.Join(path, ".git", "objects"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, ".git", "HEAD"), []byte("test-head"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(path, "src"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "src", "test.java"), []byte("test-source"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "test.iml"), []byte("test-module"), 0644)).To(Succeed())
+
+		i, err := system.LoadIgnore(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		l, err := system.NewSourceListing(path, i)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(l).To(HaveLen(2))
+		Expect(l[0].Path).To(Equal("src"))
+		Expect(l[0].SHA256).To(BeEmpty())
+		Expect(l[1].Path).To(Equal("src/test.java"))
+		Expect(l[1].SHA256).To(Equal("b558902d89ad8bb780282e8413c7f1ffeea61f551aaa6830ec7e029fd63ff403"))
+		Expect(l[1].ModificationTime).To(BeEmpty())
+	})
+}
@@ -25,11 +25,23 @@ import (
 
 func TestUnit(t *testing.T) {
 	suite := spec.New("system", spec.Report(report.Terminal{}))
+	suite("Ant", testAnt)
 	suite("Application", testApplication)
+	suite("Artifact", testArtifact)
+	suite("Binding", testBinding)
 	suite("Build", testBuild)
 	suite("Cache", testCache)
+	suite("CachePruner", testCachePruner)
+	suite("ClojureTools", testClojureTools)
 	suite("Detect", testDetect)
 	suite("Gradle", testGradle)
+	suite("Ignore", testIgnore)
+	suite("Leiningen", testLeiningen)
 	suite("Maven", testMaven)
+	suite("SBOM", testSBOM)
+	suite("Sbt", testSbt)
+	suite("TestResults", testTestResults)
+	suite("Tests", testTests)
+	suite("Wrapper", testWrapper)
 	suite.Run(t)
 }
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+const leiningenLauncher = `#!/bin/sh
+
+exec java ${LEIN_JVM_OPTS} \
+  -Dleiningen.original.pwd="${PWD}" \
+  -Dleiningen.script="${0}" \
+  -cp "%s" \
+  clojure.main -m leiningen.core.main "$@"
+`
+
+type LeiningenDistribution struct {
+	LayerContributor libpak.DependencyLayerContributor
+	Logger           bard.Logger
+}
+
+func (l LeiningenDistribution) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	l.LayerContributor.Logger = l.Logger
+
+	return l.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		jar := filepath.Join(layer.Path, "leiningen-standalone.jar")
+
+		l.Logger.Bodyf("Copying to %s", layer.Path)
+		if err := sherpa.CopyFile(artifact, jar); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to copy %s to %s\n%w", artifact.Name(), jar, err)
+		}
+
+		file := filepath.Join(layer.Path, "bin", "lein")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", filepath.Dir(file), err)
+		}
+
+		if err := ioutil.WriteFile(file, []byte(fmt.Sprintf(leiningenLauncher, jar)), 0755); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+		}
+
+		layer.Build = true
+		layer.Cache = true
+		return layer, nil
+	})
+}
+
+func (LeiningenDistribution) Name() string {
+	return "leiningen"
+}
+
+type Leiningen struct {
+	Logger bard.Logger
+}
+
+func (Leiningen) Caches(string) ([]Cache, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return []Cache{NewMavenCache(filepath.Join(u.HomeDir, ".m2"))}, nil
+}
+
+func (Leiningen) DefaultArguments() []string {
+	return []string{"uberjar"}
+}
+
+func (Leiningen) DefaultTarget() string {
+	return filepath.Join("target", "uberjar", "*-standalone.jar")
+}
+
+func (Leiningen) Detect(context libcnb.DetectContext, result *libcnb.DetectResult) error {
+	file := filepath.Join(context.Application.Path, "project.clj")
+	_, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to determine if %s exists\n%w", file, err)
+	}
+
+	result.Pass = true
+	result.Plans = append(result.Plans, libcnb.BuildPlan{
+		Provides: []libcnb.BuildPlanProvide{
+			{Name: "leiningen"},
+			{Name: "jvm-application"},
+		},
+		Requires: []libcnb.BuildPlanRequire{
+			{Name: "leiningen"},
+			{Name: "jdk"},
+		},
+	})
+
+	return nil
+}
+
+func (Leiningen) Distribution(layersPath string) string {
+	return filepath.Join(layersPath, "leiningen", "bin", "lein")
+}
+
+func (l Leiningen) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	dep, err := resolver.Resolve("leiningen", "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	return LeiningenDistribution{
+		LayerContributor: libpak.NewDependencyLayerContributor(dep, cache, plan),
+		Logger:           l.Logger,
+	}, nil
+}
+
+func (Leiningen) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
+	_, ok, err := resolver.Resolve("leiningen")
+	if err != nil {
+		return false, fmt.Errorf("unable to resolve leiningen plan entry\n%w", err)
+	}
+
+	return ok, nil
+}
+
+func (Leiningen) Wrappers() []string {
+	return []string{"lein"}
+}
@@ -17,17 +17,25 @@
 package system
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/user"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/paketo-buildpacks/libpak/crush"
 )
 
+var mavenVersion = regexp.MustCompile(`apache-maven-([^/]+)-bin\.(?:zip|tar\.gz)$`)
+
 type MavenDistribution struct {
 	LayerContributor libpak.DependencyLayerContributor
 	Logger           bard.Logger
@@ -52,20 +60,160 @@ func (MavenDistribution) Name() string {
 	return "maven"
 }
 
+type mavenProject struct {
+	Modules []string `xml:"modules>module"`
+}
+
+// MavenModules returns the paths, relative to applicationPath, of all modules in the reactor of the pom.xml in
+// applicationPath, including modules nested within other modules.
+func MavenModules(applicationPath string) ([]string, error) {
+	var modules []string
+
+	var walk func(string) error
+	walk = func(module string) error {
+		file := filepath.Join(applicationPath, filepath.FromSlash(module), "pom.xml")
+
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		var p mavenProject
+		if err := xml.Unmarshal(b, &p); err != nil {
+			return fmt.Errorf("unable to decode %s\n%w", file, err)
+		}
+
+		for _, m := range p.Modules {
+			m = strings.TrimSpace(m)
+			if strings.HasSuffix(m, ".xml") {
+				m = path.Dir(m)
+			}
+			m = path.Join(module, filepath.ToSlash(m))
+
+			modules = append(modules, m)
+			if err := walk(m); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// MavenLocalRepository returns the local repository configured with -Dmaven.repo.local in .mvn/jvm.config, $MAVEN_OPTS,
+// or .mvn/maven.config, in increasing order of precedence, as Maven applies them.  Relative paths are relative to the
+// application root.  Returns an empty string if no local repository is configured.
+func MavenLocalRepository(applicationPath string) (string, error) {
+	jvm, err := mavenOptions(filepath.Join(applicationPath, ".mvn", "jvm.config"))
+	if err != nil {
+		return "", err
+	}
+
+	config, err := mavenOptions(filepath.Join(applicationPath, ".mvn", "maven.config"))
+	if err != nil {
+		return "", err
+	}
+
+	repository := ""
+	for _, o := range append(append(jvm, strings.Fields(os.Getenv("MAVEN_OPTS"))...), config...) {
+		if strings.HasPrefix(o, "-Dmaven.repo.local=") {
+			repository = strings.Trim(strings.TrimPrefix(o, "-Dmaven.repo.local="), `"'`)
+		}
+	}
+
+	if repository != "" && !filepath.IsAbs(repository) {
+		repository = filepath.Join(applicationPath, repository)
+	}
+
+	return repository, nil
+}
+
+func mavenOptions(file string) ([]string, error) {
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return strings.Fields(string(b)), nil
+}
+
 type Maven struct {
 	Logger bard.Logger
 }
 
-func (Maven) CachePath() (string, error) {
+// AdditionalArguments returns arguments that configure Maven with the settings.xml, and optionally the
+// settings-security.xml, of a binding of kind maven, and that restrict the reactor to $BP_BUILT_MODULE and the modules
+// it depends on.
+func (m Maven) AdditionalArguments(context libcnb.BuildContext) ([]string, error) {
+	var arguments []string
+
+	b, ok, err := ResolveBinding(context.Platform, "maven")
+	if err != nil {
+		return nil, err
+	} else if ok {
+		if file, ok := SecretPath(context.Platform, b, "settings.xml"); ok {
+			m.Logger.Bodyf("Using Maven settings from binding %s", b.Name)
+			arguments = append(arguments, "--settings", file)
+		}
+
+		if file, ok := SecretPath(context.Platform, b, "settings-security.xml"); ok {
+			arguments = append(arguments, fmt.Sprintf("-Dsettings.security=%s", file))
+		}
+	}
+
+	if module := os.Getenv("BP_BUILT_MODULE"); module != "" {
+		modules, err := MavenModules(context.Application.Path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine Maven modules\n%w", err)
+		}
+
+		module = filepath.ToSlash(filepath.Clean(module))
+		if !containsString(modules, module) {
+			return nil, fmt.Errorf("module %s is not a module of the Maven reactor, modules: %s", module, modules)
+		}
+
+		m.Logger.Bodyf("Building module %s and the modules it depends on", module)
+		arguments = append(arguments, "-pl", module, "-am")
+	}
+
+	return arguments, nil
+}
+
+// Caches returns ~/.m2.  If the build configures another local repository with -Dmaven.repo.local, that repository is
+// linked to the cache's repository.
+func (Maven) Caches(applicationPath string) ([]Cache, error) {
 	u, err := user.Current()
 	if err != nil {
-		return "", fmt.Errorf("unable to determine user home directory\n%w", err)
+		return nil, fmt.Errorf("unable to determine user home directory\n%w", err)
 	}
 
-	return filepath.Join(u.HomeDir, ".m2"), nil
+	c := NewMavenCache(filepath.Join(u.HomeDir, ".m2"))
+
+	repository, err := MavenLocalRepository(applicationPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine Maven local repository\n%w", err)
+	}
+
+	if repository != "" && repository != filepath.Join(c.Path, "repository") {
+		c.Links = map[string]string{repository: "repository"}
+	}
+
+	return []Cache{c}, nil
 }
 
 func (Maven) DefaultArguments() []string {
+	if ok, _ := RunTests(); ok {
+		return []string{"package"}
+	}
+
 	return []string{"-Dmaven.test.skip=true", "package"}
 }
 
@@ -101,9 +249,16 @@ func (Maven) Distribution(layersPath string) string {
 	return filepath.Join(layersPath, "maven", "bin", "mvn")
 }
 
-func (m Maven) DistributionLayer(resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
-	dep, err := resolver.Resolve("maven", "")
+func (m Maven) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	v, source, err := DistributionVersion("BP_MAVEN_VERSION", filepath.Join(applicationPath, ".mvn", "wrapper", "maven-wrapper.properties"), mavenVersion)
 	if err != nil {
+		return nil, fmt.Errorf("unable to determine Maven version\n%w", err)
+	}
+
+	dep, err := resolver.Resolve("maven", v)
+	if v != "" && libpak.IsNoValidDependencies(err) {
+		return nil, fmt.Errorf("no Maven distribution matching version %s from %s is available\n%w", v, source, err)
+	} else if err != nil {
 		return nil, fmt.Errorf("unable to find depdency\n%w", err)
 	}
 
@@ -122,6 +277,99 @@ func (Maven) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
 	return ok, nil
 }
 
-func (Maven) Wrapper() string {
-	return "mvnw"
+func (Maven) TestReports() []string {
+	return []string{
+		filepath.Join("target", "surefire-reports", "TEST-*.xml"),
+		filepath.Join("*", "target", "surefire-reports", "TEST-*.xml"),
+		filepath.Join("target", "failsafe-reports", "TEST-*.xml"),
+		filepath.Join("*", "target", "failsafe-reports", "TEST-*.xml"),
+	}
+}
+
+func (Maven) Wrappers() []string {
+	return []string{"mvnw"}
+}
+
+func (Maven) WrapperDistribution(applicationPath string) (WrapperDistribution, bool, error) {
+	return NewWrapperDistribution(filepath.Join(applicationPath, ".mvn", "wrapper", "maven-wrapper.properties"), mavenVersion)
+}
+
+// WrapperDistributionLayer returns a layer that seeds $MAVEN_USER_HOME/wrapper/dists, defaulting to ~/.m2, with the
+// Maven dependency matching the wrapper's version.  Returns false if the version is unknown, there is no such
+// dependency, the wrapper's distributionSha256Sum does not match the dependency, or the wrapper is a 3.x wrapper, which
+// lays out wrapper/dists differently, in which case the wrapper downloads the distribution itself.
+func (m Maven) WrapperDistributionLayer(applicationPath string, distribution WrapperDistribution, resolver libpak.DependencyResolver, cache libpak.DependencyCache) (libcnb.LayerContributor, bool, error) {
+	if distribution.Version == "" {
+		return nil, false, nil
+	}
+
+	dep, err := resolver.Resolve("maven", distribution.Version)
+	if libpak.IsNoValidDependencies(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	if !distribution.Accepts(dep.SHA256) {
+		m.Logger.Bodyf("Not seeding wrapper distribution as its distributionSha256Sum %s does not match %s %s",
+			distribution.DistributionSHA256, dep.Name, dep.Version)
+		return nil, false, nil
+	}
+
+	file := filepath.Join(applicationPath, ".mvn", "wrapper", "maven-wrapper.properties")
+	if v, t, err := mavenWrapperVersion(file); err != nil {
+		return nil, false, err
+	} else if (v != "" && !strings.HasPrefix(v, "0.")) || t != "" {
+		m.Logger.Body("Not seeding wrapper distribution as maven-wrapper 3.x lays it out differently")
+		return nil, false, nil
+	}
+
+	home, err := mavenUserHome(applicationPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return WrapperDistributionLayer{
+		Dependency:        dep,
+		DependencyCache:   cache,
+		Distribution:      distribution,
+		DistributionsPath: filepath.Join(home, "wrapper", "dists"),
+		LayerName:         "maven-wrapper",
+		Logger:            m.Logger,
+	}, true, nil
+}
+
+// mavenWrapperVersion returns the wrapperVersion and distributionType declared in the wrapper properties file at path.
+// Both are only declared by 3.x wrappers, and are empty if the file does not exist.
+func mavenWrapperVersion(path string) (string, string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", fmt.Errorf("unable to determine if %s exists\n%w", path, err)
+	}
+
+	p, err := properties.LoadFile(path, properties.UTF8)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read properties from %s\n%w", path, err)
+	}
+
+	return p.GetString("wrapperVersion", ""), p.GetString("distributionType", ""), nil
+}
+
+// mavenUserHome returns $MAVEN_USER_HOME, defaulting to ~/.m2.  A relative $MAVEN_USER_HOME is relative to the
+// application root, where the wrapper is run.
+func mavenUserHome(applicationPath string) (string, error) {
+	if home, ok := os.LookupEnv("MAVEN_USER_HOME"); ok {
+		if !filepath.IsAbs(home) {
+			home = filepath.Join(applicationPath, home)
+		}
+		return home, nil
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return filepath.Join(u.HomeDir, ".m2"), nil
 }
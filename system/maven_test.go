@@ -17,8 +17,10 @@
 package system_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/user"
 	"path/filepath"
 	"testing"
 
@@ -72,7 +74,7 @@ func testMaven(t *testing.T, context spec.G, it spec.S) {
 
 			dc := libpak.DependencyCache{CachePath: "testdata"}
 
-			d, err := maven.DistributionLayer(dr, dc, &libcnb.BuildpackPlan{})
+			d, err := maven.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
 			Expect(err).NotTo(HaveOccurred())
 
 			layer, err := ctx.Layers.Layer("test-layer")
@@ -86,6 +88,73 @@ func testMaven(t *testing.T, context spec.G, it spec.S) {
 			Expect(filepath.Join(layer.Path, "fixture-marker")).To(BeARegularFile())
 		})
 
+		context("distribution version", func() {
+			var (
+				dc libpak.DependencyCache
+				dr libpak.DependencyResolver
+			)
+
+			it.Before(func() {
+				dr = libpak.DependencyResolver{
+					Dependencies: []libpak.BuildpackDependency{
+						{ID: "maven", Version: "1.1.1", Stacks: []string{"test-stack-id"}},
+						{ID: "maven", Version: "2.2.2", Stacks: []string{"test-stack-id"}},
+						{ID: "maven", Version: "3.3.3", Stacks: []string{"test-stack-id"}},
+					},
+					StackID: "test-stack-id",
+				}
+
+				Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, ".mvn", "wrapper"), 0755)).To(Succeed())
+			})
+
+			it("uses latest version without wrapper properties", func() {
+				d, err := maven.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(d.(system.MavenDistribution).LayerContributor.Dependency.Version).To(Equal("3.3.3"))
+			})
+
+			it("uses version from wrapper properties", func() {
+				Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, ".mvn", "wrapper"), "maven-wrapper.properties"),
+					[]byte(`distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/2.2.2/apache-maven-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+				d, err := maven.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(d.(system.MavenDistribution).LayerContributor.Dependency.Version).To(Equal("2.2.2"))
+			})
+
+			it("fails if version is not available", func() {
+				dr.Dependencies = dr.Dependencies[:1]
+				Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, ".mvn", "wrapper"), "maven-wrapper.properties"),
+					[]byte(`distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/2.2.2/apache-maven-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+				_, err := maven.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+				Expect(err).To(MatchError(HavePrefix(fmt.Sprintf("no Maven distribution matching version 2.2.2 from %s is available",
+					filepath.Join(filepath.Join(ctx.Application.Path, ".mvn", "wrapper"), "maven-wrapper.properties")))))
+			})
+
+			context("$BP_MAVEN_VERSION", func() {
+				it.Before(func() {
+					Expect(os.Setenv("BP_MAVEN_VERSION", "1.*")).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.Unsetenv("BP_MAVEN_VERSION")).To(Succeed())
+				})
+
+				it("uses version from $BP_MAVEN_VERSION", func() {
+					Expect(ioutil.WriteFile(filepath.Join(filepath.Join(ctx.Application.Path, ".mvn", "wrapper"), "maven-wrapper.properties"),
+						[]byte(`distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/2.2.2/apache-maven-2.2.2-bin.zip`), 0644)).To(Succeed())
+
+					d, err := maven.DistributionLayer(ctx.Application.Path, dr, dc, &libcnb.BuildpackPlan{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(d.(system.MavenDistribution).LayerContributor.Dependency.Version).To(Equal("1.1.1"))
+				})
+			})
+		})
+
 		it("it participates", func() {
 			pr := libpak.PlanEntryResolver{Plan: libcnb.BuildpackPlan{
 				Entries: []libcnb.BuildpackPlanEntry{
@@ -97,6 +166,323 @@ func testMaven(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("WrapperDistributionLayer", func() {
+		var (
+			dc   libpak.DependencyCache
+			dr   libpak.DependencyResolver
+			path string
+		)
+
+		it.Before(func() {
+			var err error
+
+			path, err = ioutil.TempDir("", "maven")
+			Expect(err).NotTo(HaveOccurred())
+
+			dr = libpak.DependencyResolver{
+				Dependencies: []libpak.BuildpackDependency{
+					{ID: "maven", Version: "1.1.1", SHA256: "test-sha256", Stacks: []string{"test-stack-id"}},
+				},
+				StackID: "test-stack-id",
+			}
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("MAVEN_USER_HOME")).To(Succeed())
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns false without version", func() {
+			_, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns false without matching dependency", func() {
+			_, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "2.2.2"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns layer seeding Maven user home", func() {
+			Expect(os.Setenv("MAVEN_USER_HOME", "/test-maven-home")).To(Succeed())
+
+			l, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			Expect(l.Name()).To(Equal("maven-wrapper"))
+			Expect(l.(system.WrapperDistributionLayer).Dependency.Version).To(Equal("1.1.1"))
+			Expect(l.(system.WrapperDistributionLayer).DistributionsPath).To(Equal(filepath.Join("/test-maven-home", "wrapper", "dists")))
+		})
+
+		it("returns layer seeding relative Maven user home", func() {
+			Expect(os.Setenv("MAVEN_USER_HOME", ".m2")).To(Succeed())
+
+			l, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			Expect(l.(system.WrapperDistributionLayer).DistributionsPath).To(Equal(filepath.Join(path, ".m2", "wrapper", "dists")))
+		})
+
+		it("returns layer with 0.5.x wrapper", func() {
+			Expect(os.MkdirAll(filepath.Join(path, ".mvn", "wrapper"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "wrapper", "maven-wrapper.properties"),
+				[]byte("distributionUrl=https://localhost/apache-maven-1.1.1-bin.zip\n"), 0644)).To(Succeed())
+
+			_, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		it("returns false with 3.x wrapper", func() {
+			Expect(os.MkdirAll(filepath.Join(path, ".mvn", "wrapper"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "wrapper", "maven-wrapper.properties"),
+				[]byte("wrapperVersion=3.3.2\ndistributionType=only-script\ndistributionUrl=https://localhost/apache-maven-1.1.1-bin.zip\n"), 0644)).To(Succeed())
+
+			_, ok, err := maven.WrapperDistributionLayer(path, system.WrapperDistribution{Version: "1.1.1"}, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("returns false with mismatched checksum", func() {
+			d := system.WrapperDistribution{
+				WrapperProperties: system.WrapperProperties{DistributionSHA256: "test-other-sha256"},
+				Version:           "1.1.1",
+			}
+
+			_, ok, err := maven.WrapperDistributionLayer(path, d, dr, dc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("Caches", func() {
+		var (
+			home string
+			path string
+		)
+
+		it.Before(func() {
+			u, err := user.Current()
+			Expect(err).NotTo(HaveOccurred())
+			home = filepath.Join(u.HomeDir, ".m2")
+
+			path, err = ioutil.TempDir("", "maven")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(path, ".mvn"), 0755)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("MAVEN_OPTS")).To(Succeed())
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns ~/.m2", func() {
+			caches, err := maven.Caches(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caches).To(HaveLen(1))
+			Expect(caches[0].Path).To(Equal(home))
+			Expect(caches[0].Links).To(BeEmpty())
+		})
+
+		it("links local repository", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "maven.config"), []byte("-B -Dmaven.repo.local=test-repository\n"), 0644)).
+				To(Succeed())
+
+			caches, err := maven.Caches(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caches[0].Path).To(Equal(home))
+			Expect(caches[0].Links).To(Equal(map[string]string{filepath.Join(path, "test-repository"): "repository"}))
+		})
+	})
+
+	context("MavenLocalRepository", func() {
+		var (
+			path string
+		)
+
+		it.Before(func() {
+			var err error
+
+			path, err = ioutil.TempDir("", "maven")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(path, ".mvn"), 0755)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("MAVEN_OPTS")).To(Succeed())
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns empty without local repository", func() {
+			Expect(system.MavenLocalRepository(path)).To(BeEmpty())
+		})
+
+		it("reads jvm.config", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "jvm.config"), []byte("-Xmx1g -Dmaven.repo.local=/test-jvm-config"), 0644)).
+				To(Succeed())
+
+			Expect(system.MavenLocalRepository(path)).To(Equal("/test-jvm-config"))
+		})
+
+		it("prefers $MAVEN_OPTS to jvm.config", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "jvm.config"), []byte("-Dmaven.repo.local=/test-jvm-config"), 0644)).
+				To(Succeed())
+			Expect(os.Setenv("MAVEN_OPTS", "-Dmaven.repo.local=/test-maven-opts")).To(Succeed())
+
+			Expect(system.MavenLocalRepository(path)).To(Equal("/test-maven-opts"))
+		})
+
+		it("prefers maven.config to $MAVEN_OPTS", func() {
+			Expect(os.Setenv("MAVEN_OPTS", "-Dmaven.repo.local=/test-maven-opts")).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "maven.config"), []byte("-Dmaven.repo.local=/test-maven-config"), 0644)).
+				To(Succeed())
+
+			Expect(system.MavenLocalRepository(path)).To(Equal("/test-maven-config"))
+		})
+
+		it("resolves relative to application root", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, ".mvn", "maven.config"), []byte(`-Dmaven.repo.local="test-repository"`), 0644)).
+				To(Succeed())
+
+			Expect(system.MavenLocalRepository(path)).To(Equal(filepath.Join(path, "test-repository")))
+		})
+	})
+
+	context("AdditionalArguments", func() {
+		var (
+			ctx libcnb.BuildContext
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Application.Path, err = ioutil.TempDir("", "maven-application")
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx.Platform.Path = "/platform"
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Application.Path)).To(Succeed())
+		})
+
+		it("returns no arguments without binding", func() {
+			Expect(maven.AdditionalArguments(ctx)).To(BeEmpty())
+		})
+
+		it("returns settings argument", func() {
+			ctx.Platform.Bindings = libcnb.Bindings{
+				{
+					Name:     "test-binding",
+					Metadata: map[string]string{libcnb.BindingKind: "maven"},
+					Secret:   map[string]string{"settings.xml": "test-settings"},
+				},
+			}
+
+			Expect(maven.AdditionalArguments(ctx)).To(Equal([]string{
+				"--settings", "/platform/bindings/test-binding/secret/settings.xml",
+			}))
+		})
+
+		it("returns settings security argument", func() {
+			ctx.Platform.Bindings = libcnb.Bindings{
+				{
+					Name:     "test-binding",
+					Metadata: map[string]string{libcnb.BindingKind: "maven"},
+					Secret: map[string]string{
+						"settings.xml":          "test-settings",
+						"settings-security.xml": "test-settings-security",
+					},
+				},
+			}
+
+			Expect(maven.AdditionalArguments(ctx)).To(Equal([]string{
+				"--settings", "/platform/bindings/test-binding/secret/settings.xml",
+				"-Dsettings.security=/platform/bindings/test-binding/secret/settings-security.xml",
+			}))
+		})
+
+		it("fails with multiple bindings", func() {
+			ctx.Platform.Bindings = libcnb.Bindings{
+				{Name: "test-binding-1", Metadata: map[string]string{libcnb.BindingKind: "maven"}},
+				{Name: "test-binding-2", Metadata: map[string]string{libcnb.BindingKind: "maven"}},
+			}
+
+			_, err := maven.AdditionalArguments(ctx)
+			Expect(err).To(HaveOccurred())
+		})
+
+		context("$BP_BUILT_MODULE", func() {
+			it.Before(func() {
+				Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "pom.xml"), []byte(`<project>
+  <modules>
+    <module>test-alpha</module>
+    <module>test-bravo/pom.xml</module>
+  </modules>
+</project>`), 0644)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "test-alpha"), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-alpha", "pom.xml"), []byte(`<project>
+  <modules>
+    <module>test-charlie</module>
+  </modules>
+</project>`), 0644)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "test-alpha", "test-charlie"), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-alpha", "test-charlie", "pom.xml"),
+					[]byte(`<project/>`), 0644)).To(Succeed())
+
+				Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "test-bravo"), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(ctx.Application.Path, "test-bravo", "pom.xml"), []byte(`<project/>`), 0644)).
+					To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("BP_BUILT_MODULE")).To(Succeed())
+			})
+
+			it("lists modules", func() {
+				Expect(system.MavenModules(ctx.Application.Path)).To(Equal([]string{
+					"test-alpha", "test-alpha/test-charlie", "test-bravo",
+				}))
+			})
+
+			it("returns module arguments", func() {
+				Expect(os.Setenv("BP_BUILT_MODULE", "test-alpha/test-charlie/")).To(Succeed())
+
+				Expect(maven.AdditionalArguments(ctx)).To(Equal([]string{"-pl", "test-alpha/test-charlie", "-am"}))
+			})
+
+			it("fails with unknown module", func() {
+				Expect(os.Setenv("BP_BUILT_MODULE", "test-delta")).To(Succeed())
+
+				_, err := maven.AdditionalArguments(ctx)
+				Expect(err).To(MatchError("module test-delta is not a module of the Maven reactor, modules: [test-alpha test-alpha/test-charlie test-bravo]"))
+			})
+		})
+	})
+
+	context("DefaultArguments", func() {
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILD_RUN_TESTS")).To(Succeed())
+		})
+
+		it("skips tests", func() {
+			Expect(maven.DefaultArguments()).To(Equal([]string{"-Dmaven.test.skip=true", "package"}))
+		})
+
+		it("runs tests with $BP_BUILD_RUN_TESTS", func() {
+			Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+
+			Expect(maven.DefaultArguments()).To(Equal([]string{"package"}))
+		})
+	})
+
 	context("Detect", func() {
 		var (
 			ctx    libcnb.DetectContext
@@ -6,6 +6,8 @@ import (
 	libcnb "github.com/buildpacks/libcnb"
 	libpak "github.com/paketo-buildpacks/libpak"
 
+	system "github.com/paketo-buildpacks/build-system/system"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,20 +16,22 @@ type System struct {
 	mock.Mock
 }
 
-// CachePath provides a mock function with given fields:
-func (_m *System) CachePath() (string, error) {
-	ret := _m.Called()
+// Caches provides a mock function with given fields: applicationPath
+func (_m *System) Caches(applicationPath string) ([]system.Cache, error) {
+	ret := _m.Called(applicationPath)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
-		r0 = rf()
+	var r0 []system.Cache
+	if rf, ok := ret.Get(0).(func(string) []system.Cache); ok {
+		r0 = rf(applicationPath)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]system.Cache)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(applicationPath)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -93,13 +97,13 @@ func (_m *System) Distribution(layersPath string) string {
 	return r0
 }
 
-// DistributionLayer provides a mock function with given fields: resolver, cache, plan
-func (_m *System) DistributionLayer(resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
-	ret := _m.Called(resolver, cache, plan)
+// DistributionLayer provides a mock function with given fields: applicationPath, resolver, cache, plan
+func (_m *System) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	ret := _m.Called(applicationPath, resolver, cache, plan)
 
 	var r0 libcnb.LayerContributor
-	if rf, ok := ret.Get(0).(func(libpak.DependencyResolver, libpak.DependencyCache, *libcnb.BuildpackPlan) libcnb.LayerContributor); ok {
-		r0 = rf(resolver, cache, plan)
+	if rf, ok := ret.Get(0).(func(string, libpak.DependencyResolver, libpak.DependencyCache, *libcnb.BuildpackPlan) libcnb.LayerContributor); ok {
+		r0 = rf(applicationPath, resolver, cache, plan)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(libcnb.LayerContributor)
@@ -137,15 +141,17 @@ func (_m *System) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
 	return r0, r1
 }
 
-// Wrapper provides a mock function with given fields:
-func (_m *System) Wrapper() string {
+// Wrappers provides a mock function with given fields:
+func (_m *System) Wrappers() []string {
 	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	return r0
@@ -0,0 +1,361 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+var (
+	libraryName   = regexp.MustCompile(`^(.+?)-([0-9][^-]*(?:-.+)?)$`)
+	pomProperties = regexp.MustCompile(`^META-INF/maven/[^/]+/[^/]+/pom\.properties$`)
+)
+
+// SBOMComponent is a library packaged in a built artifact.
+type SBOMComponent struct {
+
+	// ArtifactID is the Maven artifact id of the library, or its file name if it has no Maven metadata.
+	ArtifactID string
+
+	// GroupID is the optional Maven group id of the library.
+	GroupID string
+
+	// Path is the location of the library within the artifact.
+	Path string
+
+	// SHA256 is the optional SHA256 checksum of the library.
+	SHA256 string
+
+	// Version is the optional version of the library.
+	Version string
+}
+
+// PURL returns the package URL of the component.  Returns an empty string if the component has no Maven coordinates.
+func (s SBOMComponent) PURL() string {
+	if s.GroupID == "" || s.Version == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("pkg:maven/%s/%s@%s", s.GroupID, s.ArtifactID, s.Version)
+}
+
+// ScanArtifact returns the libraries packaged in the ZIP artifact at path.  Libraries are JARs in BOOT-INF/lib and
+// WEB-INF/lib, identified by their META-INF/maven/**/pom.properties if present, and libraries shaded into the artifact,
+// identified by its own META-INF/maven/**/pom.properties.
+func ScanArtifact(path string) ([]SBOMComponent, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+	defer z.Close()
+
+	var components []SBOMComponent
+	for _, f := range z.File {
+		switch {
+		case (strings.HasPrefix(f.Name, "BOOT-INF/lib/") || strings.HasPrefix(f.Name, "WEB-INF/lib/")) &&
+			strings.HasSuffix(f.Name, ".jar"):
+
+			c, err := scanLibrary(f)
+			if err != nil {
+				return nil, fmt.Errorf("unable to scan %s in %s\n%w", f.Name, path, err)
+			}
+			components = append(components, c)
+		case pomProperties.MatchString(f.Name):
+			c, err := readPomProperties(f)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s in %s\n%w", f.Name, path, err)
+			}
+			c.Path = f.Name
+			components = append(components, c)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Path < components[j].Path
+	})
+
+	return components, nil
+}
+
+func scanLibrary(file *zip.File) (SBOMComponent, error) {
+	in, err := file.Open()
+	if err != nil {
+		return SBOMComponent{}, fmt.Errorf("unable to open %s\n%w", file.Name, err)
+	}
+	defer in.Close()
+
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		return SBOMComponent{}, fmt.Errorf("unable to read %s\n%w", file.Name, err)
+	}
+
+	s := sha256.Sum256(b)
+	c := SBOMComponent{Path: file.Name, SHA256: hex.EncodeToString(s[:])}
+
+	// Libraries that are not valid ZIPs are still recorded, identified by their file name.
+	if z, err := zip.NewReader(bytes.NewReader(b), int64(len(b))); err == nil {
+		for _, f := range z.File {
+			if !pomProperties.MatchString(f.Name) {
+				continue
+			}
+
+			p, err := readPomProperties(f)
+			if err != nil {
+				return SBOMComponent{}, err
+			}
+
+			c.ArtifactID, c.GroupID, c.Version = p.ArtifactID, p.GroupID, p.Version
+			return c, nil
+		}
+	}
+
+	name := strings.TrimSuffix(path.Base(file.Name), ".jar")
+	if m := libraryName.FindStringSubmatch(name); m != nil {
+		c.ArtifactID, c.Version = m[1], m[2]
+	} else {
+		c.ArtifactID = name
+	}
+
+	return c, nil
+}
+
+func readPomProperties(file *zip.File) (SBOMComponent, error) {
+	in, err := file.Open()
+	if err != nil {
+		return SBOMComponent{}, fmt.Errorf("unable to open %s\n%w", file.Name, err)
+	}
+	defer in.Close()
+
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		return SBOMComponent{}, fmt.Errorf("unable to read %s\n%w", file.Name, err)
+	}
+
+	p, err := properties.Load(b, properties.UTF8)
+	if err != nil {
+		return SBOMComponent{}, fmt.Errorf("unable to parse %s\n%w", file.Name, err)
+	}
+
+	return SBOMComponent{
+		ArtifactID: p.GetString("artifactId", ""),
+		GroupID:    p.GetString("groupId", ""),
+		Version:    p.GetString("version", ""),
+	}, nil
+}
+
+// CycloneDX is a CycloneDX 1.2 JSON bill of materials.
+type CycloneDX struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Components  []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXComponent struct {
+	Type    string          `json:"type"`
+	Group   string          `json:"group,omitempty"`
+	Name    string          `json:"name"`
+	Version string          `json:"version,omitempty"`
+	PURL    string          `json:"purl,omitempty"`
+	Hashes  []CycloneDXHash `json:"hashes,omitempty"`
+}
+
+type CycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+func NewCycloneDX(components []SBOMComponent) CycloneDX {
+	c := CycloneDX{BOMFormat: "CycloneDX", SpecVersion: "1.2", Version: 1, Components: []CycloneDXComponent{}}
+
+	for _, s := range components {
+		d := CycloneDXComponent{
+			Type:    "library",
+			Group:   s.GroupID,
+			Name:    s.ArtifactID,
+			Version: s.Version,
+			PURL:    s.PURL(),
+		}
+
+		if s.SHA256 != "" {
+			d.Hashes = []CycloneDXHash{{Algorithm: "SHA-256", Content: s.SHA256}}
+		}
+
+		c.Components = append(c.Components, d)
+	}
+
+	return c
+}
+
+// SPDX is an SPDX 2.2 JSON document.
+type SPDX struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo `json:"creationInfo"`
+	Packages          []SPDXPackage    `json:"packages"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	SPDXID           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo,omitempty"`
+	DownloadLocation string             `json:"downloadLocation"`
+	FilesAnalyzed    bool               `json:"filesAnalyzed"`
+	LicenseConcluded string             `json:"licenseConcluded"`
+	LicenseDeclared  string             `json:"licenseDeclared"`
+	CopyrightText    string             `json:"copyrightText"`
+	Checksums        []SPDXChecksum     `json:"checksums,omitempty"`
+	ExternalRefs     []SPDXExternalRefs `json:"externalRefs,omitempty"`
+}
+
+type SPDXChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type SPDXExternalRefs struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// NewSPDX creates an SPDX document named name.  The document namespace is derived from the name and components so
+// that identical contents produce an identical namespace.
+func NewSPDX(name string, created time.Time, components []SBOMComponent) SPDX {
+	h := sha256.New()
+	for _, c := range components {
+		_, _ = fmt.Fprintf(h, "%s:%s:%s:%s:%s\n", c.Path, c.GroupID, c.ArtifactID, c.Version, c.SHA256)
+	}
+
+	s := SPDX{
+		SPDXVersion:       "SPDX-2.2",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://paketo.io/spdx/%s-%s", name, hex.EncodeToString(h.Sum(nil))),
+		CreationInfo: SPDXCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: paketo-buildpacks/build-system"},
+		},
+		Packages: []SPDXPackage{},
+	}
+
+	for i, c := range components {
+		p := SPDXPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			Name:             c.ArtifactID,
+			VersionInfo:      c.Version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+		}
+
+		if c.SHA256 != "" {
+			p.Checksums = []SPDXChecksum{{Algorithm: "SHA256", ChecksumValue: c.SHA256}}
+		}
+
+		if purl := c.PURL(); purl != "" {
+			p.ExternalRefs = []SPDXExternalRefs{
+				{ReferenceCategory: "PACKAGE_MANAGER", ReferenceType: "purl", ReferenceLocator: purl},
+			}
+		}
+
+		s.Packages = append(s.Packages, p)
+	}
+
+	return s
+}
+
+// SBOM contributes the bills of materials written by the application layer to a launch layer, exposing each of them
+// in the layer metadata as well.
+type SBOM struct {
+	Logger bard.Logger
+
+	// Path is the directory the application layer wrote the bills of materials to.
+	Path string
+}
+
+func NewSBOM(path string) SBOM {
+	return SBOM{Path: path}
+}
+
+func (s SBOM) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.RemoveAll(layer.Path); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", layer.Path, err)
+	}
+
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", layer.Path, err)
+	}
+
+	layer.Metadata = map[string]interface{}{}
+	for _, format := range []string{"cyclonedx", "spdx"} {
+		source := filepath.Join(s.Path, fmt.Sprintf("%s.json", format))
+
+		b, err := ioutil.ReadFile(source)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to read %s\n%w", source, err)
+		}
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to decode %s\n%w", source, err)
+		}
+		layer.Metadata[format] = m
+
+		file := filepath.Join(layer.Path, filepath.Base(source))
+		s.Logger.Bodyf("Writing %s", file)
+		if err := ioutil.WriteFile(file, b, 0644); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+		}
+	}
+
+	layer.Launch = true
+	return layer, nil
+}
+
+func (SBOM) Name() string {
+	return "sbom"
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/sclevine/spec"
+)
+
+func testSBOM(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	writeZip := func(entries map[string][]byte) []byte {
+		b := bytes.NewBuffer(nil)
+		z := zip.NewWriter(b)
+		for name, content := range entries {
+			w, err := z.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(z.Close()).To(Succeed())
+		return b.Bytes()
+	}
+
+	sha := func(b []byte) string {
+		s := sha256.Sum256(b)
+		return hex.EncodeToString(s[:])
+	}
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "sbom")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("ScanArtifact", func() {
+		it("scans libraries", func() {
+			alpha := writeZip(map[string][]byte{
+				"META-INF/maven/test.group/test-alpha/pom.properties": []byte("groupId=test.group\nartifactId=test-alpha\nversion=1.1.1\n"),
+			})
+			bravo := writeZip(map[string][]byte{"test-file": []byte("")})
+
+			Expect(ioutil.WriteFile(filepath.Join(path, "test.jar"), writeZip(map[string][]byte{
+				"BOOT-INF/lib/test-alpha-1.1.1.jar":                    alpha,
+				"WEB-INF/lib/test-bravo-2.2.2.RELEASE.jar":             bravo,
+				"BOOT-INF/classes/test.properties":                     []byte(""),
+				"META-INF/maven/test.group/test-shaded/pom.properties": []byte("groupId=test.group\nartifactId=test-shaded\nversion=3.3.3\n"),
+			}), 0644)).To(Succeed())
+
+			Expect(system.ScanArtifact(filepath.Join(path, "test.jar"))).To(Equal([]system.SBOMComponent{
+				{
+					ArtifactID: "test-alpha",
+					GroupID:    "test.group",
+					Path:       "BOOT-INF/lib/test-alpha-1.1.1.jar",
+					SHA256:     sha(alpha),
+					Version:    "1.1.1",
+				},
+				{
+					ArtifactID: "test-shaded",
+					GroupID:    "test.group",
+					Path:       "META-INF/maven/test.group/test-shaded/pom.properties",
+					Version:    "3.3.3",
+				},
+				{
+					ArtifactID: "test-bravo",
+					Path:       "WEB-INF/lib/test-bravo-2.2.2.RELEASE.jar",
+					SHA256:     sha(bravo),
+					Version:    "2.2.2.RELEASE",
+				},
+			}))
+		})
+	})
+
+	context("documents", func() {
+		components := []system.SBOMComponent{
+			{ArtifactID: "test-alpha", GroupID: "test.group", SHA256: "test-sha256", Version: "1.1.1"},
+			{ArtifactID: "test-bravo"},
+		}
+
+		it("creates CycloneDX", func() {
+			Expect(system.NewCycloneDX(components)).To(Equal(system.CycloneDX{
+				BOMFormat:   "CycloneDX",
+				SpecVersion: "1.2",
+				Version:     1,
+				Components: []system.CycloneDXComponent{
+					{
+						Type:    "library",
+						Group:   "test.group",
+						Name:    "test-alpha",
+						Version: "1.1.1",
+						PURL:    "pkg:maven/test.group/test-alpha@1.1.1",
+						Hashes:  []system.CycloneDXHash{{Algorithm: "SHA-256", Content: "test-sha256"}},
+					},
+					{Type: "library", Name: "test-bravo"},
+				},
+			}))
+		})
+
+		it("creates SPDX", func() {
+			s := system.NewSPDX("test-name", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), components)
+
+			Expect(s.SPDXVersion).To(Equal("SPDX-2.2"))
+			Expect(s.Name).To(Equal("test-name"))
+			Expect(s.DocumentNamespace).To(HavePrefix("https://paketo.io/spdx/test-name-"))
+			Expect(s.CreationInfo.Created).To(Equal("2020-01-01T00:00:00Z"))
+			Expect(s.Packages).To(Equal([]system.SPDXPackage{
+				{
+					SPDXID:           "SPDXRef-Package-1",
+					Name:             "test-alpha",
+					VersionInfo:      "1.1.1",
+					DownloadLocation: "NOASSERTION",
+					LicenseConcluded: "NOASSERTION",
+					LicenseDeclared:  "NOASSERTION",
+					CopyrightText:    "NOASSERTION",
+					Checksums:        []system.SPDXChecksum{{Algorithm: "SHA256", ChecksumValue: "test-sha256"}},
+					ExternalRefs: []system.SPDXExternalRefs{
+						{ReferenceCategory: "PACKAGE_MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:maven/test.group/test-alpha@1.1.1"},
+					},
+				},
+				{
+					SPDXID:           "SPDXRef-Package-2",
+					Name:             "test-bravo",
+					DownloadLocation: "NOASSERTION",
+					LicenseConcluded: "NOASSERTION",
+					LicenseDeclared:  "NOASSERTION",
+					CopyrightText:    "NOASSERTION",
+				},
+			}))
+			Expect(system.NewSPDX("test-name", time.Now(), components).DocumentNamespace).To(Equal(s.DocumentNamespace))
+		})
+	})
+
+	context("SBOM", func() {
+		var (
+			ctx libcnb.BuildContext
+		)
+
+		it.Before(func() {
+			var err error
+
+			ctx.Layers.Path, err = ioutil.TempDir("", "sbom-layers")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+		})
+
+		it("contributes bills of materials", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "cyclonedx.json"), []byte(`{"bomFormat": "CycloneDX"}`), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "spdx.json"), []byte(`{"spdxVersion": "SPDX-2.2"}`), 0644)).To(Succeed())
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = system.NewSBOM(path).Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Launch).To(BeTrue())
+			Expect(layer.Metadata).To(Equal(map[string]interface{}{
+				"cyclonedx": map[string]interface{}{"bomFormat": "CycloneDX"},
+				"spdx":      map[string]interface{}{"spdxVersion": "SPDX-2.2"},
+			}))
+			Expect(filepath.Join(layer.Path, "cyclonedx.json")).To(BeARegularFile())
+			Expect(filepath.Join(layer.Path, "spdx.json")).To(BeARegularFile())
+		})
+	})
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/crush"
+)
+
+type SbtDistribution struct {
+	LayerContributor libpak.DependencyLayerContributor
+	Logger           bard.Logger
+}
+
+func (s SbtDistribution) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	s.LayerContributor.Logger = s.Logger
+
+	return s.LayerContributor.Contribute(layer, func(artifact *os.File) (libcnb.Layer, error) {
+		s.Logger.Bodyf("Expanding to %s", layer.Path)
+		if err := crush.ExtractTarGz(artifact, layer.Path, 1); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to expand sbt\n%w", err)
+		}
+
+		layer.Build = true
+		layer.Cache = true
+		return layer, nil
+	})
+}
+
+func (SbtDistribution) Name() string {
+	return "sbt"
+}
+
+type Sbt struct {
+	Logger bard.Logger
+}
+
+func (Sbt) Caches(string) ([]Cache, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine user home directory\n%w", err)
+	}
+
+	return []Cache{
+		NewCache(filepath.Join(u.HomeDir, ".sbt")),
+		NewCache(filepath.Join(u.HomeDir, ".ivy2")),
+		NewCache(filepath.Join(u.HomeDir, ".cache", "coursier")),
+	}, nil
+}
+
+func (Sbt) DefaultArguments() []string {
+	return []string{"universal:packageBin"}
+}
+
+func (Sbt) DefaultTarget() string {
+	return filepath.Join("target", "universal", "*.zip")
+}
+
+func (Sbt) Detect(context libcnb.DetectContext, result *libcnb.DetectResult) error {
+	files := []string{
+		filepath.Join(context.Application.Path, "build.sbt"),
+		filepath.Join(context.Application.Path, "project", "build.properties"),
+	}
+
+	for _, f := range files {
+		_, err := os.Stat(f)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to determine if %s exists\n%w", f, err)
+		}
+
+		result.Pass = true
+		result.Plans = append(result.Plans, libcnb.BuildPlan{
+			Provides: []libcnb.BuildPlanProvide{
+				{Name: "sbt"},
+				{Name: "jvm-application"},
+			},
+			Requires: []libcnb.BuildPlanRequire{
+				{Name: "sbt"},
+				{Name: "jdk"},
+			},
+		})
+
+		return nil
+	}
+
+	return nil
+}
+
+func (Sbt) Distribution(layersPath string) string {
+	return filepath.Join(layersPath, "sbt", "bin", "sbt")
+}
+
+func (s Sbt) DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error) {
+	dep, err := resolver.Resolve("sbt", "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find dependency\n%w", err)
+	}
+
+	return SbtDistribution{
+		LayerContributor: libpak.NewDependencyLayerContributor(dep, cache, plan),
+		Logger:           s.Logger,
+	}, nil
+}
+
+func (Sbt) Participate(resolver libpak.PlanEntryResolver) (bool, error) {
+	_, ok, err := resolver.Resolve("sbt")
+	if err != nil {
+		return false, fmt.Errorf("unable to resolve sbt plan entry\n%w", err)
+	}
+
+	return ok, nil
+}
+
+// Wrappers returns the sbt-extras launcher, which is conventionally committed as sbt, or as sbtx.
+func (Sbt) Wrappers() []string {
+	return []string{"sbt", "sbtx"}
+}
@@ -24,12 +24,54 @@ import (
 //go:generate mockery -name System -case=underscore
 
 type System interface {
-	CachePath() (string, error)
+	Caches(applicationPath string) ([]Cache, error)
 	Detect(context libcnb.DetectContext, result *libcnb.DetectResult) error
 	DefaultArguments() []string
 	DefaultTarget() string
 	Distribution(layersPath string) string
-	DistributionLayer(resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error)
+	DistributionLayer(applicationPath string, resolver libpak.DependencyResolver, cache libpak.DependencyCache, plan *libcnb.BuildpackPlan) (libcnb.LayerContributor, error)
 	Participate(resolver libpak.PlanEntryResolver) (bool, error)
-	Wrapper() string
+	Wrappers() []string
+}
+
+//go:generate mockery -name ArgumentsProvider -case=underscore
+
+// ArgumentsProvider is implemented by a System that derives arguments, in addition to the default arguments, from the
+// build.
+type ArgumentsProvider interface {
+	AdditionalArguments(context libcnb.BuildContext) ([]string, error)
+}
+
+//go:generate mockery -name LayersProvider -case=underscore
+
+// LayersProvider is implemented by a System that contributes layers, such as configuration from bindings, in addition
+// to its distribution and caches.
+type LayersProvider interface {
+	AdditionalLayers(context libcnb.BuildContext) ([]libcnb.LayerContributor, error)
+}
+
+//go:generate mockery -name TestReportsProvider -case=underscore
+
+// TestReportsProvider is implemented by a System whose test reports are collected when tests are run.
+type TestReportsProvider interface {
+	TestReports() []string
+}
+
+//go:generate mockery -name WrapperDistributionProvider -case=underscore
+
+// WrapperDistributionProvider is implemented by a System whose wrapper declares the distribution it downloads, so that
+// the distribution can be recorded and seeded from the buildpack's dependencies.
+type WrapperDistributionProvider interface {
+	WrapperDistribution(applicationPath string) (WrapperDistribution, bool, error)
+	WrapperDistributionLayer(applicationPath string, distribution WrapperDistribution, resolver libpak.DependencyResolver, cache libpak.DependencyCache) (libcnb.LayerContributor, bool, error)
+}
+
+func containsString(candidates []string, value string) bool {
+	for _, c := range candidates {
+		if c == value {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// TestResults contributes the test reports harvested by the application layer, along with a normalized JSON summary
+// of them, to a layer that is cached but not launched, so that the reports do not bloat the image.  The counts of the
+// summary are also recorded in PlanMetadata, if set, which is exported with the bill of materials as an image label.
+type TestResults struct {
+	Logger bard.Logger
+
+	// Path is the directory the application layer harvested the test reports to.
+	Path string
+
+	// PlanMetadata is the metadata of the buildpack plan entry that records the counts of the summary.
+	PlanMetadata map[string]interface{}
+}
+
+func NewTestResults(path string) TestResults {
+	return TestResults{Path: path}
+}
+
+func (t TestResults) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.RemoveAll(layer.Path); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", layer.Path, err)
+	}
+
+	reports := filepath.Join(layer.Path, "reports")
+	if err := os.MkdirAll(reports, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", reports, err)
+	}
+
+	if _, err := os.Stat(t.Path); err == nil {
+		if err := copyTree(t.Path, reports); err != nil {
+			return libcnb.Layer{}, fmt.Errorf("unable to copy %s to %s\n%w", t.Path, reports, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to stat %s\n%w", t.Path, err)
+	}
+
+	var files []string
+	if err := filepath.Walk(reports, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && strings.HasSuffix(path, ".xml") {
+			files = append(files, path)
+		}
+
+		return nil
+	}); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to list test reports in %s\n%w", reports, err)
+	}
+
+	summary, err := NewTestSummary(files)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to summarize test reports\n%w", err)
+	}
+
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode test summary\n%w", err)
+	}
+
+	file := filepath.Join(layer.Path, "summary.json")
+	t.Logger.Bodyf("Writing test summary to %s", file)
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	// Flat string values so that the entries can be copied verbatim into image labels.
+	layer.Metadata = map[string]interface{}{
+		"errors":   strconv.Itoa(summary.Errors),
+		"failures": strconv.Itoa(summary.Failures),
+		"reports":  strconv.Itoa(summary.Reports),
+		"skipped":  strconv.Itoa(summary.Skipped),
+		"tests":    strconv.Itoa(summary.Tests),
+	}
+
+	if t.PlanMetadata != nil {
+		for k, v := range layer.Metadata {
+			t.PlanMetadata[k] = v
+		}
+	}
+
+	layer.Cache = true
+	return layer, nil
+}
+
+func (TestResults) Name() string {
+	return "test-results"
+}
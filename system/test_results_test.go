@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/sclevine/spec"
+)
+
+func testTestResults(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		ctx  libcnb.BuildContext
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		ctx.Layers.Path, err = ioutil.TempDir("", "test-results-layers")
+		Expect(err).NotTo(HaveOccurred())
+
+		path, err = ioutil.TempDir("", "test-results")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(ctx.Layers.Path)).To(Succeed())
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("contributes test results", func() {
+		Expect(os.MkdirAll(filepath.Join(path, "target", "surefire-reports"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(path, "target", "surefire-reports", "TEST-test.xml"),
+			[]byte(`<testsuite tests="2" skipped="1"><testcase classname="test" name="passes"/><testcase classname="test" name="skips"><skipped/></testcase></testsuite>`),
+			0644)).To(Succeed())
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = system.NewTestResults(path).Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Build).To(BeFalse())
+		Expect(layer.Cache).To(BeTrue())
+		Expect(layer.Launch).To(BeFalse())
+		Expect(layer.Metadata).To(Equal(map[string]interface{}{
+			"errors":   "0",
+			"failures": "0",
+			"reports":  "1",
+			"skipped":  "1",
+			"tests":    "2",
+		}))
+
+		Expect(filepath.Join(layer.Path, "reports", "target", "surefire-reports", "TEST-test.xml")).To(BeARegularFile())
+		Expect(ioutil.ReadFile(filepath.Join(layer.Path, "summary.json"))).To(MatchJSON(`{
+  "errors": 0,
+  "failed": null,
+  "failures": 0,
+  "reports": 1,
+  "skipped": 1,
+  "tests": 2
+}`))
+	})
+
+	it("records counts in plan metadata", func() {
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		r := system.NewTestResults(filepath.Join(path, "does-not-exist"))
+		r.PlanMetadata = map[string]interface{}{}
+
+		layer, err = r.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.PlanMetadata).To(Equal(layer.Metadata))
+	})
+
+	it("contributes empty test results without reports", func() {
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = system.NewTestResults(filepath.Join(path, "does-not-exist")).Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Metadata).To(HaveKeyWithValue("reports", "0"))
+		Expect(filepath.Join(layer.Path, "summary.json")).To(BeARegularFile())
+	})
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// RunTests returns whether $BP_BUILD_RUN_TESTS requests that tests be run during the build.  Defaults to false.
+func RunTests() (bool, error) {
+	s, ok := os.LookupEnv("BP_BUILD_RUN_TESTS")
+	if !ok || s == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse $BP_BUILD_RUN_TESTS value %s\n%w", s, err)
+	}
+
+	return b, nil
+}
+
+// TestSummary is a summary of a collection of JUnit XML test reports.
+type TestSummary struct {
+
+	// Errors is the number of tests that errored.
+	Errors int `json:"errors"`
+
+	// Failed is the names of the tests that failed or errored.
+	Failed []string `json:"failed"`
+
+	// Failures is the number of tests that failed.
+	Failures int `json:"failures"`
+
+	// Reports is the number of reports summarized.
+	Reports int `json:"reports"`
+
+	// Skipped is the number of tests that were skipped.
+	Skipped int `json:"skipped"`
+
+	// Tests is the number of tests that were run.
+	Tests int `json:"tests"`
+}
+
+type junitTestCase struct {
+	ClassName string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Error     *struct{} `xml:"error"`
+	Failure   *struct{} `xml:"failure"`
+}
+
+type junitTestSuite struct {
+	Errors    int             `xml:"errors,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	Tests     int             `xml:"tests,attr"`
+}
+
+type junitReport struct {
+	XMLName    xml.Name
+	TestSuites []junitTestSuite `xml:"testsuite"`
+	junitTestSuite
+}
+
+// FindTestReports returns the sorted paths of the test reports matching patterns, relative to applicationPath.
+func FindTestReports(applicationPath string, patterns []string) ([]string, error) {
+	var files []string
+	for _, p := range patterns {
+		candidates, err := filepath.Glob(filepath.Join(applicationPath, p))
+		if err != nil {
+			return nil, fmt.Errorf("unable to find files with %s\n%w", p, err)
+		}
+		files = append(files, candidates...)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// NewTestSummary summarizes the JUnit XML test reports in files.  Both <testsuite> and <testsuites> documents are
+// supported and any other document is ignored.
+func NewTestSummary(files []string) (TestSummary, error) {
+	var s TestSummary
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return TestSummary{}, fmt.Errorf("unable to read %s\n%w", f, err)
+		}
+
+		var r junitReport
+		if err := xml.Unmarshal(b, &r); err != nil {
+			return TestSummary{}, fmt.Errorf("unable to decode test report %s\n%w", f, err)
+		}
+
+		switch r.XMLName.Local {
+		case "testsuites":
+			for _, t := range r.TestSuites {
+				s.add(t)
+			}
+		case "testsuite":
+			s.add(r.junitTestSuite)
+		default:
+			continue
+		}
+
+		s.Reports++
+	}
+
+	return s, nil
+}
+
+// Passed returns whether no tests failed or errored.
+func (t TestSummary) Passed() bool {
+	return t.Failures == 0 && t.Errors == 0
+}
+
+func (t TestSummary) String() string {
+	return fmt.Sprintf("%d tests, %d failures, %d errors, %d skipped", t.Tests, t.Failures, t.Errors, t.Skipped)
+}
+
+func (t *TestSummary) add(suite junitTestSuite) {
+	t.Errors += suite.Errors
+	t.Failures += suite.Failures
+	t.Skipped += suite.Skipped
+	t.Tests += suite.Tests
+
+	for _, c := range suite.TestCases {
+		if c.Failure != nil || c.Error != nil {
+			t.Failed = append(t.Failed, fmt.Sprintf("%s.%s", c.ClassName, c.Name))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/sclevine/spec"
+)
+
+func testTests(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("RunTests", func() {
+		it.After(func() {
+			Expect(os.Unsetenv("BP_BUILD_RUN_TESTS")).To(Succeed())
+		})
+
+		it("defaults to false", func() {
+			Expect(system.RunTests()).To(BeFalse())
+		})
+
+		it("parses $BP_BUILD_RUN_TESTS", func() {
+			Expect(os.Setenv("BP_BUILD_RUN_TESTS", "true")).To(Succeed())
+			Expect(system.RunTests()).To(BeTrue())
+		})
+
+		it("fails with invalid $BP_BUILD_RUN_TESTS", func() {
+			Expect(os.Setenv("BP_BUILD_RUN_TESTS", "test-value")).To(Succeed())
+
+			_, err := system.RunTests()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	context("NewTestSummary", func() {
+		var (
+			path string
+		)
+
+		it.Before(func() {
+			var err error
+
+			path, err = ioutil.TempDir("", "tests")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(path, "reports"), 0755)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("returns empty summary without reports", func() {
+			files, err := system.FindTestReports(path, []string{filepath.Join("reports", "TEST-*.xml")})
+			Expect(err).NotTo(HaveOccurred())
+
+			s, err := system.NewTestSummary(files)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(s).To(BeZero())
+			Expect(s.Passed()).To(BeTrue())
+		})
+
+		it("summarizes reports", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "reports", "TEST-test.Alpha.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="test.Alpha" tests="3" failures="1" errors="0" skipped="1">
+  <testcase classname="test.Alpha" name="passes"/>
+  <testcase classname="test.Alpha" name="fails"><failure message="test-message">test-trace</failure></testcase>
+  <testcase classname="test.Alpha" name="skips"><skipped/></testcase>
+</testsuite>
+`), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path, "reports", "TEST-test.Bravo.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="test.Bravo" tests="2" failures="0" errors="1" skipped="0">
+    <testcase classname="test.Bravo" name="passes"/>
+    <testcase classname="test.Bravo" name="errors"><error message="test-message">test-trace</error></testcase>
+  </testsuite>
+</testsuites>
+`), 0644)).To(Succeed())
+
+			files, err := system.FindTestReports(path, []string{filepath.Join("reports", "TEST-*.xml")})
+			Expect(err).NotTo(HaveOccurred())
+
+			s, err := system.NewTestSummary(files)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(s).To(Equal(system.TestSummary{
+				Errors:   1,
+				Failed:   []string{"test.Alpha.fails", "test.Bravo.errors"},
+				Failures: 1,
+				Reports:  2,
+				Skipped:  1,
+				Tests:    5,
+			}))
+			Expect(s.Passed()).To(BeFalse())
+			Expect(s.String()).To(Equal("5 tests, 1 failures, 1 errors, 1 skipped"))
+		})
+
+		it("fails with invalid report", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "reports", "TEST-test.Alpha.xml"), []byte("<testsuite"), 0644)).To(Succeed())
+
+			_, err := system.NewTestSummary([]string{filepath.Join(path, "reports", "TEST-test.Alpha.xml")})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/crush"
+)
+
+// WrapperProperties is the distribution configuration of a build system wrapper.
+type WrapperProperties struct {
+
+	// DistributionURL is the location the wrapper downloads the distribution from.
+	DistributionURL string
+
+	// DistributionSHA256 is the optional SHA256 checksum the wrapper verifies the distribution against.
+	DistributionSHA256 string
+}
+
+// NewWrapperProperties reads the wrapper properties file at path.  Returns false if the file does not exist.
+func NewWrapperProperties(path string) (WrapperProperties, bool, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return WrapperProperties{}, false, nil
+	} else if err != nil {
+		return WrapperProperties{}, false, fmt.Errorf("unable to determine if %s exists\n%w", path, err)
+	}
+
+	p, err := properties.LoadFile(path, properties.UTF8)
+	if err != nil {
+		return WrapperProperties{}, false, fmt.Errorf("unable to read properties from %s\n%w", path, err)
+	}
+
+	w := WrapperProperties{
+		DistributionURL:    p.GetString("distributionUrl", ""),
+		DistributionSHA256: p.GetString("distributionSha256Sum", ""),
+	}
+
+	if w.DistributionURL == "" {
+		return WrapperProperties{}, false, fmt.Errorf("no distributionUrl in %s", path)
+	}
+
+	return w, true, nil
+}
+
+// Version returns the distribution version from the DistributionURL.  The version is the first submatch of pattern.
+func (w WrapperProperties) Version(pattern *regexp.Regexp) (string, error) {
+	m := pattern.FindStringSubmatch(w.DistributionURL)
+	if m == nil {
+		return "", fmt.Errorf("unable to determine version from %s", w.DistributionURL)
+	}
+
+	return m[1], nil
+}
+
+// Accepts returns whether the wrapper would accept a distribution with the SHA256 checksum, that is whether it
+// declares no checksum or the same one.
+func (w WrapperProperties) Accepts(sha256 string) bool {
+	return w.DistributionSHA256 == "" || strings.EqualFold(w.DistributionSHA256, sha256)
+}
+
+// InstallPath returns the directory, relative to the wrapper's distributions directory, that the Gradle and pre-3.x
+// Maven wrappers install the distribution into: the distribution's file name without its extension, and then the base 36
+// MD5 hash of the DistributionURL.
+func (w WrapperProperties) InstallPath() (string, error) {
+	u, err := url.Parse(w.DistributionURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse distribution URL %s\n%w", w.DistributionURL, err)
+	}
+
+	name := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	hash := md5.Sum([]byte(w.DistributionURL))
+
+	return filepath.Join(name, new(big.Int).SetBytes(hash[:]).Text(36)), nil
+}
+
+// WrapperDistribution is the distribution a build system wrapper is configured to use.
+type WrapperDistribution struct {
+	WrapperProperties
+
+	// Version is the version of the distribution.  Empty if it cannot be determined from the DistributionURL.
+	Version string
+}
+
+// NewWrapperDistribution reads the distribution from the wrapper properties file at path.  The version is the first
+// submatch of pattern.  Returns false if the file does not exist.
+func NewWrapperDistribution(path string, pattern *regexp.Regexp) (WrapperDistribution, bool, error) {
+	w, ok, err := NewWrapperProperties(path)
+	if err != nil || !ok {
+		return WrapperDistribution{}, ok, err
+	}
+
+	v, _ := w.Version(pattern)
+	return WrapperDistribution{WrapperProperties: w, Version: v}, true, nil
+}
+
+// DistributionVersion returns the version constraint for a build system distribution and a description of where it
+// was configured.  The value of the environment variable named env takes precedence over the version declared in the
+// wrapper properties file at path.  Returns an empty version if neither is configured.
+func DistributionVersion(env string, path string, pattern *regexp.Regexp) (string, string, error) {
+	if s, ok := os.LookupEnv(env); ok {
+		return s, fmt.Sprintf("$%s", env), nil
+	}
+
+	w, ok, err := NewWrapperProperties(path)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read wrapper properties\n%w", err)
+	} else if !ok {
+		return "", "", nil
+	}
+
+	v, err := w.Version(pattern)
+	if err != nil {
+		return "", "", err
+	}
+
+	return v, path, nil
+}
+
+// WrapperDistributionLayer seeds a wrapper's distributions directory with a distribution from the buildpack's
+// dependencies, laid out as the Gradle and Maven wrappers install it, so that the wrapper does not download it.  The
+// distributions directory is typically within a cache, so the layer itself sets no flags.
+type WrapperDistributionLayer struct {
+	Dependency        libpak.BuildpackDependency
+	DependencyCache   libpak.DependencyCache
+	Distribution      WrapperDistribution
+	DistributionsPath string
+	LayerName         string
+	Logger            bard.Logger
+}
+
+func (w WrapperDistributionLayer) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	install, err := w.Distribution.InstallPath()
+	if err != nil {
+		return libcnb.Layer{}, err
+	}
+	install = filepath.Join(w.DistributionsPath, install)
+
+	u, err := url.Parse(w.Distribution.DistributionURL)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to parse distribution URL %s\n%w", w.Distribution.DistributionURL, err)
+	}
+	marker := filepath.Join(install, fmt.Sprintf("%s.ok", path.Base(u.Path)))
+
+	if _, err := os.Stat(marker); err == nil {
+		w.Logger.Bodyf("Reusing wrapper distribution %s", install)
+		return layer, nil
+	} else if !os.IsNotExist(err) {
+		return libcnb.Layer{}, fmt.Errorf("unable to stat %s\n%w", marker, err)
+	}
+
+	w.DependencyCache.Logger = w.Logger
+	artifact, err := w.DependencyCache.Artifact(w.Dependency)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to get dependency %s\n%w", w.Dependency.ID, err)
+	}
+	defer artifact.Close()
+
+	if err := os.RemoveAll(install); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to remove %s\n%w", install, err)
+	}
+
+	kind, err := Artifact{Path: artifact.Name()}.Kind()
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to determine kind of %s\n%w", artifact.Name(), err)
+	}
+
+	// The wrappers expect the distribution's single top-level directory within the install directory.
+	w.Logger.Bodyf("Seeding wrapper distribution %s %s to %s", w.Dependency.Name, w.Dependency.Version, install)
+	switch kind {
+	case TarGzArtifact:
+		err = crush.ExtractTarGz(artifact, install, 0)
+	case ZipArtifact:
+		err = crush.ExtractZip(artifact, install, 0)
+	default:
+		err = fmt.Errorf("%s is neither a ZIP nor a TAR.GZ", artifact.Name())
+	}
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to expand %s\n%w", artifact.Name(), err)
+	}
+
+	if err := ioutil.WriteFile(marker, []byte{}, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", marker, err)
+	}
+
+	return layer, nil
+}
+
+func (w WrapperDistributionLayer) Name() string {
+	return w.LayerName
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/build-system/system"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/sclevine/spec"
+)
+
+func testWrapper(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path    string
+		pattern = regexp.MustCompile(`test-distribution-(.+)\.zip$`)
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = ioutil.TempDir("", "wrapper")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	context("NewWrapperProperties", func() {
+		it("returns false if file does not exist", func() {
+			_, ok, err := system.NewWrapperProperties(filepath.Join(path, "test.properties"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("reads distribution properties", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "test.properties"), []byte(`distributionUrl=https\://localhost/test-distribution-1.1.1.zip
+distributionSha256Sum=test-sha256
+`), 0644)).To(Succeed())
+
+			w, ok, err := system.NewWrapperProperties(filepath.Join(path, "test.properties"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(w).To(Equal(system.WrapperProperties{
+				DistributionURL:    "https://localhost/test-distribution-1.1.1.zip",
+				DistributionSHA256: "test-sha256",
+			}))
+			Expect(w.Version(pattern)).To(Equal("1.1.1"))
+		})
+
+		it("fails without distributionUrl", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "test.properties"), []byte("test-key=test-value"), 0644)).To(Succeed())
+
+			_, _, err := system.NewWrapperProperties(filepath.Join(path, "test.properties"))
+			Expect(err).To(MatchError(HavePrefix("no distributionUrl in")))
+		})
+	})
+
+	context("NewWrapperDistribution", func() {
+		it("returns false if file does not exist", func() {
+			_, ok, err := system.NewWrapperDistribution(filepath.Join(path, "test.properties"), pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		it("reads distribution and version", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "test.properties"), []byte(`distributionUrl=https\://localhost/test-distribution-1.1.1.zip
+distributionSha256Sum=test-sha256
+`), 0644)).To(Succeed())
+
+			d, ok, err := system.NewWrapperDistribution(filepath.Join(path, "test.properties"), pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(d).To(Equal(system.WrapperDistribution{
+				WrapperProperties: system.WrapperProperties{
+					DistributionURL:    "https://localhost/test-distribution-1.1.1.zip",
+					DistributionSHA256: "test-sha256",
+				},
+				Version: "1.1.1",
+			}))
+		})
+
+		it("omits unrecognized version", func() {
+			Expect(ioutil.WriteFile(filepath.Join(path, "test.properties"), []byte(`distributionUrl=https\://localhost/other-distribution.zip`), 0644)).To(Succeed())
+
+			d, ok, err := system.NewWrapperDistribution(filepath.Join(path, "test.properties"), pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(d.Version).To(BeEmpty())
+		})
+	})
+
+	context("DistributionVersion", func() {
+		it("returns empty version if not configured", func() {
+			Expect(system.DistributionVersion("TEST_VERSION", filepath.Join(path, "test.properties"), pattern)).To(BeEmpty())
+		})
+
+		it("returns version from wrapper properties", func() {
+			file := filepath.Join(path, "test.properties")
+			Expect(ioutil.WriteFile(file, []byte("distributionUrl=https://localhost/test-distribution-1.1.1.zip"), 0644)).To(Succeed())
+
+			v, source, err := system.DistributionVersion("TEST_VERSION", file, pattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(Equal("1.1.1"))
+			Expect(source).To(Equal(file))
+		})
+
+		it("fails with unrecognized distribution", func() {
+			file := filepath.Join(path, "test.properties")
+			Expect(ioutil.WriteFile(file, []byte("distributionUrl=https://localhost/test-other.zip"), 0644)).To(Succeed())
+
+			_, _, err := system.DistributionVersion("TEST_VERSION", file, pattern)
+			Expect(err).To(MatchError("unable to determine version from https://localhost/test-other.zip"))
+		})
+
+		context("$TEST_VERSION", func() {
+			it.Before(func() {
+				Expect(os.Setenv("TEST_VERSION", "2.2.2")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("TEST_VERSION")).To(Succeed())
+			})
+
+			it("prefers environment variable", func() {
+				file := filepath.Join(path, "test.properties")
+				Expect(ioutil.WriteFile(file, []byte("distributionUrl=https://localhost/test-distribution-1.1.1.zip"), 0644)).To(Succeed())
+
+				v, source, err := system.DistributionVersion("TEST_VERSION", file, pattern)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(v).To(Equal("2.2.2"))
+				Expect(source).To(Equal("$TEST_VERSION"))
+			})
+		})
+	})
+
+	context("InstallPath", func() {
+		it("returns distribution name and URL hash", func() {
+			w := system.WrapperProperties{DistributionURL: "https://services.gradle.org/distributions/gradle-6.3-bin.zip"}
+
+			Expect(w.InstallPath()).To(Equal(filepath.Join("gradle-6.3-bin", "8tpu6egwsccjzp10c1jckl0rx")))
+		})
+	})
+
+	context("WrapperDistributionLayer", func() {
+		var (
+			layer libcnb.Layer
+			w     system.WrapperDistributionLayer
+		)
+
+		it.Before(func() {
+			layer = libcnb.Layer{Path: filepath.Join(path, "layer")}
+
+			w = system.WrapperDistributionLayer{
+				Dependency: libpak.BuildpackDependency{
+					ID:      "gradle",
+					Version: "1.1.1",
+					URI:     "https://localhost/stub-gradle.zip",
+					SHA256:  "5fa754fef54387acdf1ab3107e4ddcaf141e713cd5f946afad4edfbf9461928f",
+					Stacks:  []string{"test-stack-id"},
+				},
+				DependencyCache: libpak.DependencyCache{CachePath: "testdata"},
+				Distribution: system.WrapperDistribution{
+					WrapperProperties: system.WrapperProperties{DistributionURL: "https://localhost/gradle-1.1.1-bin.zip"},
+					Version:           "1.1.1",
+				},
+				DistributionsPath: filepath.Join(path, "dists"),
+				LayerName:         "test-wrapper",
+			}
+		})
+
+		it("seeds distribution", func() {
+			install, err := w.Distribution.InstallPath()
+			Expect(err).NotTo(HaveOccurred())
+			install = filepath.Join(path, "dists", install)
+
+			layer, err = w.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.Build).To(BeFalse())
+			Expect(layer.Cache).To(BeFalse())
+			Expect(layer.Launch).To(BeFalse())
+			Expect(filepath.Join(install, "gradle-0.0.0", "fixture-marker")).To(BeARegularFile())
+			Expect(filepath.Join(install, "gradle-1.1.1-bin.zip.ok")).To(BeARegularFile())
+		})
+
+		it("seeds TAR.GZ distribution", func() {
+			w.Dependency = libpak.BuildpackDependency{
+				ID:      "maven",
+				Version: "1.1.1",
+				URI:     "https://localhost/stub-maven.tar.gz",
+				SHA256:  "31ba45356e22aff670af88170f43ff82328e6f323c3ce891ba422bd1031e3308",
+				Stacks:  []string{"test-stack-id"},
+			}
+			w.Distribution.DistributionURL = "https://localhost/apache-maven-1.1.1-bin.zip"
+
+			install, err := w.Distribution.InstallPath()
+			Expect(err).NotTo(HaveOccurred())
+			install = filepath.Join(path, "dists", install)
+
+			_, err = w.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(install, "apache-maven-0.0.0", "fixture-marker")).To(BeARegularFile())
+			Expect(filepath.Join(install, "apache-maven-1.1.1-bin.zip.ok")).To(BeARegularFile())
+		})
+
+		it("reuses seeded distribution", func() {
+			install, err := w.Distribution.InstallPath()
+			Expect(err).NotTo(HaveOccurred())
+			install = filepath.Join(path, "dists", install)
+
+			Expect(os.MkdirAll(install, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(install, "gradle-1.1.1-bin.zip.ok"), []byte{}, 0644)).To(Succeed())
+
+			_, err = w.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(install, "gradle-0.0.0")).NotTo(BeADirectory())
+		})
+
+		it("replaces partially installed distribution", func() {
+			install, err := w.Distribution.InstallPath()
+			Expect(err).NotTo(HaveOccurred())
+			install = filepath.Join(path, "dists", install)
+
+			Expect(os.MkdirAll(filepath.Join(install, "gradle-0.0.0"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(install, "gradle-0.0.0", "partial"), []byte{}, 0644)).To(Succeed())
+
+			_, err = w.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(install, "gradle-0.0.0", "partial")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(install, "gradle-0.0.0", "fixture-marker")).To(BeARegularFile())
+		})
+	})
+}